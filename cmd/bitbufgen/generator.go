@@ -0,0 +1,660 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/galaco/bitbuf/internal/tag"
+)
+
+// maxSampleDepth bounds how deeply generated tests populate recursive types
+const maxSampleDepth = 4
+
+// basicType describes how a predeclared Go type is encoded
+type basicType struct {
+	// class is one of "bool", "int", "uint", "float" or "string"
+	class string
+	bits  uint
+	// platform is set for int and uint, which are encoded in the width of the
+	// platform, as Marshal does
+	platform bool
+}
+
+var basicTypes = map[string]basicType{
+	"bool":    {"bool", 1, false},
+	"int8":    {"int", 8, false},
+	"int16":   {"int", 16, false},
+	"int32":   {"int", 32, false},
+	"rune":    {"int", 32, false},
+	"int64":   {"int", 64, false},
+	"int":     {"int", 64, true},
+	"uint8":   {"uint", 8, false},
+	"byte":    {"uint", 8, false},
+	"uint16":  {"uint", 16, false},
+	"uint32":  {"uint", 32, false},
+	"uint64":  {"uint", 64, false},
+	"uint":    {"uint", 64, true},
+	"float32": {"float", 32, false},
+	"float64": {"float", 64, false},
+	"string":  {"string", 0, false},
+}
+
+// structField is an exported, non-skipped field of a struct
+type structField struct {
+	name string
+	expr ast.Expr
+	opts tag.Options
+}
+
+// pkg holds the type declarations of the package being generated for
+type pkg struct {
+	name    string
+	structs map[string]*ast.StructType
+	// named holds the underlying type of every other named type
+	named map[string]ast.Expr
+}
+
+func parsePackage(dir string) (*pkg, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pkg{structs: map[string]*ast.StructType{}, named: map[string]ast.Expr{}}
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		p.name = file.Name.Name
+		ast.Inspect(file, func(node ast.Node) bool {
+			if spec, ok := node.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					p.structs[spec.Name.Name] = st
+				} else if spec.Assign == token.NoPos {
+					p.named[spec.Name.Name] = spec.Type
+				}
+			}
+			return true
+		})
+	}
+	if p.name == "" {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+	return p, nil
+}
+
+// basicOf returns how an identifier is encoded, and the predeclared type it resolves to.
+// Named types declared in the package are resolved through their underlying type.
+func (p *pkg) basicOf(name string) (string, basicType, bool) {
+	// Bounded, as an invalid package may declare named types in a cycle
+	for i := 0; i <= len(p.named); i++ {
+		underlying, ok := p.named[name].(*ast.Ident)
+		if !ok {
+			break
+		}
+		name = underlying.Name
+	}
+	basic, ok := basicTypes[name]
+	return name, basic, ok
+}
+
+// underlying returns the underlying type of a named composite type, such as a
+// named slice, or nil if name is not one.
+func (p *pkg) underlying(name string) ast.Expr {
+	if expr, ok := p.named[name]; ok {
+		if _, isIdent := expr.(*ast.Ident); !isIdent {
+			return expr
+		}
+	}
+	return nil
+}
+
+// fields returns the fields of a struct that are encoded, in declaration order
+func (p *pkg) fields(typeName string) ([]structField, error) {
+	st, ok := p.structs[typeName]
+	if !ok {
+		return nil, fmt.Errorf("struct type %s not found", typeName)
+	}
+
+	fields := make([]structField, 0)
+	for _, field := range st.Fields.List {
+		tagValue := ""
+		if field.Tag != nil {
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tagValue = reflect.StructTag(raw).Get(tag.Name)
+		}
+		opts, err := tag.Parse(tagValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", typeName, err)
+		}
+		if opts.Skip {
+			continue
+		}
+
+		names := make([]string, 0)
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		if len(field.Names) == 0 {
+			// Embedded fields are named after their type
+			names = append(names, types.ExprString(field.Type))
+		}
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+			fields = append(fields, structField{name: name, expr: field.Type, opts: opts})
+		}
+	}
+	return fields, nil
+}
+
+// generator accumulates generated source, and the imports it requires
+type generator struct {
+	pkg     *pkg
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// source returns the formatted source, with the header and imports prepended
+func (g *generator) source() ([]byte, error) {
+	std := make([]string, 0)
+	external := make([]string, 0)
+	for path := range g.imports {
+		if strings.Contains(path, ".") {
+			external = append(external, strconv.Quote(path))
+		} else {
+			std = append(std, strconv.Quote(path))
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(external)
+
+	header := fmt.Sprintf("// Code generated by bitbufgen; DO NOT EDIT.\n\npackage %s\n\nimport (\n%s\n\n%s\n)\n", g.pkg.name, strings.Join(std, "\n"), strings.Join(external, "\n"))
+	return format.Source(append([]byte(header), g.buf.Bytes()...))
+}
+
+// generate returns the source of the methods and tests for the named types,
+// and any struct types they contain.
+func generate(p *pkg, typeNames []string) ([]byte, []byte, error) {
+	queue := append([]string{}, typeNames...)
+	seen := map[string]bool{}
+	ordered := make([]string, 0)
+	for len(queue) > 0 {
+		typeName := queue[0]
+		queue = queue[1:]
+		if seen[typeName] {
+			continue
+		}
+		seen[typeName] = true
+		ordered = append(ordered, typeName)
+
+		fields, err := p.fields(typeName)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, field := range fields {
+			queue = append(queue, p.nestedStructs(field.expr)...)
+		}
+	}
+
+	code := &generator{pkg: p, imports: map[string]bool{"github.com/galaco/bitbuf": true}}
+	test := &generator{pkg: p, imports: map[string]bool{"bytes": true, "reflect": true, "testing": true, "github.com/galaco/bitbuf": true}}
+	for _, typeName := range ordered {
+		fields, _ := p.fields(typeName)
+		if err := code.decodeMethod(typeName, fields); err != nil {
+			return nil, nil, err
+		}
+		if err := code.encodeMethod(typeName, fields); err != nil {
+			return nil, nil, err
+		}
+		if err := test.roundTripTest(typeName); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	source, err := code.source()
+	if err != nil {
+		return nil, nil, err
+	}
+	testSource, err := test.source()
+	if err != nil {
+		return nil, nil, err
+	}
+	return source, testSource, nil
+}
+
+// nestedStructs returns the package struct types referenced by expr
+func (p *pkg) nestedStructs(expr ast.Expr) []string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := p.structs[e.Name]; ok {
+			return []string{e.Name}
+		}
+		if underlying := p.underlying(e.Name); underlying != nil {
+			return p.nestedStructs(underlying)
+		}
+	case *ast.ArrayType:
+		return p.nestedStructs(e.Elt)
+	}
+	return nil
+}
+
+// arrayLength returns the length of a fixed array type, or -1 for a slice
+func arrayLength(e *ast.ArrayType) (int, error) {
+	if e.Len == nil {
+		return -1, nil
+	}
+	lit, ok := e.Len.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, fmt.Errorf("array length %s must be an integer literal", types.ExprString(e.Len))
+	}
+	return strconv.Atoi(lit.Value)
+}
+
+func (g *generator) decodeMethod(typeName string, fields []structField) error {
+	g.printf("// DecodeBits reads %s from buf\n", typeName)
+	g.printf("func (v *%s) DecodeBits(buf *bitbuf.Reader) error {\n", typeName)
+	for _, field := range fields {
+		if err := g.decodeValue("v."+field.name, field.expr, field.opts, 0); err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, field.name, err)
+		}
+	}
+	g.printf("return nil\n}\n\n")
+	return nil
+}
+
+func (g *generator) decodeValue(target string, expr ast.Expr, opts tag.Options, depth int) error {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := g.pkg.structs[e.Name]; ok {
+			g.printf("if err := %s.DecodeBits(buf); err != nil {\nreturn err\n}\n", target)
+			return nil
+		}
+		if underlying := g.pkg.underlying(e.Name); underlying != nil {
+			return g.decodeValue(target, underlying, opts, depth)
+		}
+		_, basic, ok := g.pkg.basicOf(e.Name)
+		if !ok {
+			return fmt.Errorf("unsupported type %s", e.Name)
+		}
+		call, result, err := g.decodeCall(basic, opts)
+		if err != nil {
+			return err
+		}
+		value := "x"
+		if result != e.Name {
+			value = e.Name + "(x)"
+		}
+		g.printf("{\nx, err := buf.%s\nif err != nil {\nreturn err\n}\n%s = %s\n}\n", call, target, value)
+		return nil
+	case *ast.ArrayType:
+		length, err := arrayLength(e)
+		if err != nil {
+			return err
+		}
+		index := fmt.Sprintf("i%d", depth)
+		if length < 0 {
+			if opts.LenBits == 0 {
+				return fmt.Errorf("slices require a len option")
+			}
+			// Every element takes at least 1 bit, so a length that can't fit is rejected before allocating
+			g.printf("{\nn, err := buf.ReadUint32Bits(%d)\nif err != nil {\nreturn err\n}\n", opts.LenBits)
+			g.printf("if err := buf.CheckAvailable(uint(n)); err != nil {\nreturn err\n}\n%s = make(%s, n)\n}\n", target, types.ExprString(e))
+		} else if opts.Vec3Coord {
+			if length != 3 || types.ExprString(e.Elt) != "float32" {
+				return fmt.Errorf("vec3coord requires [3]float32, not %s", types.ExprString(e))
+			}
+			g.printf("{\nx, err := buf.ReadBitVec3Coord()\nif err != nil {\nreturn err\n}\n%s = x\n}\n", target)
+			return nil
+		}
+		g.printf("for %s := range %s {\n", index, target)
+		if err := g.decodeValue(target+"["+index+"]", e.Elt, opts, depth+1); err != nil {
+			return err
+		}
+		g.printf("}\n")
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+// decodeCall returns the Reader method call that decodes a basic type, and the type it returns
+func (g *generator) decodeCall(basic basicType, opts tag.Options) (string, string, error) {
+	switch basic.class {
+	case "bool":
+		return "ReadOneBit()", "bool", nil
+	case "string":
+		return fmt.Sprintf("ReadString(%d)", opts.Max), "string", nil
+	case "float":
+		switch {
+		case opts.Coord:
+			return "ReadBitCoord()", "float32", nil
+		case opts.Normal:
+			return "ReadBitNormal()", "float32", nil
+		case opts.Angle != 0:
+			return fmt.Sprintf("ReadBitAngle(%d)", opts.Angle), "float32", nil
+		case basic.bits == 32:
+			return "ReadFloat32()", "float32", nil
+		default:
+			return "ReadFloat64()", "float64", nil
+		}
+	}
+
+	if opts.VarInt {
+		switch {
+		case basic.bits > 32 && opts.Signed:
+			return "ReadSignedVarInt64()", "int64", nil
+		case basic.bits > 32:
+			return "ReadVarInt64()", "uint64", nil
+		case opts.Signed:
+			return "ReadSignedVarInt32()", "int32", nil
+		default:
+			return "ReadVarInt32()", "uint32", nil
+		}
+	}
+	numBits := basic.bits
+	if opts.Bits != 0 {
+		numBits = opts.Bits
+	}
+	if numBits > basic.bits {
+		return "", "", fmt.Errorf("%d bits do not fit in a %d bit integer", numBits, basic.bits)
+	}
+	if basic.platform && opts.Bits == 0 {
+		g.imports["math/bits"] = true
+		if opts.Signed {
+			return "ReadInt64Bits(bits.UintSize)", "int64", nil
+		}
+		return "ReadUint64Bits(bits.UintSize)", "uint64", nil
+	}
+	switch {
+	case numBits > 32 && opts.Signed:
+		return fmt.Sprintf("ReadInt64Bits(%d)", numBits), "int64", nil
+	case numBits > 32:
+		return fmt.Sprintf("ReadUint64Bits(%d)", numBits), "uint64", nil
+	case opts.Signed:
+		return fmt.Sprintf("ReadInt32Bits(%d)", numBits), "int32", nil
+	default:
+		return fmt.Sprintf("ReadUint32Bits(%d)", numBits), "uint32", nil
+	}
+}
+
+func (g *generator) encodeMethod(typeName string, fields []structField) error {
+	g.printf("// EncodeBits writes %s to buf\n", typeName)
+	g.printf("func (v *%s) EncodeBits(buf *bitbuf.Writer) error {\n", typeName)
+	for _, field := range fields {
+		if err := g.encodeValue("v."+field.name, field.expr, field.opts, 0); err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, field.name, err)
+		}
+	}
+	g.printf("return nil\n}\n\n")
+	return nil
+}
+
+func (g *generator) encodeValue(target string, expr ast.Expr, opts tag.Options, depth int) error {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := g.pkg.structs[e.Name]; ok {
+			g.printf("if err := %s.EncodeBits(buf); err != nil {\nreturn err\n}\n", target)
+			return nil
+		}
+		if underlying := g.pkg.underlying(e.Name); underlying != nil {
+			return g.encodeValue(target, underlying, opts, depth)
+		}
+		name, basic, ok := g.pkg.basicOf(e.Name)
+		if !ok {
+			return fmt.Errorf("unsupported type %s", e.Name)
+		}
+		if name != e.Name && basic.class != "int" && basic.class != "uint" {
+			// Integers are always converted by encodeCall
+			target = name + "(" + target + ")"
+		}
+		if basic.class == "string" {
+			g.encodeString(target, opts)
+			return nil
+		}
+		call, err := g.encodeCall(target, basic, opts)
+		if err != nil {
+			return err
+		}
+		g.printf("if err := buf.%s; err != nil {\nreturn err\n}\n", call)
+		return nil
+	case *ast.ArrayType:
+		length, err := arrayLength(e)
+		if err != nil {
+			return err
+		}
+		index := fmt.Sprintf("i%d", depth)
+		if length < 0 {
+			if opts.LenBits == 0 {
+				return fmt.Errorf("slices require a len option")
+			}
+			g.imports["fmt"] = true
+			g.printf("if uint64(len(%s)) >= 1<<%d {\nreturn fmt.Errorf(\"bitbuf: %%d elements do not fit in a %d bit length\", len(%s))\n}\n", target, opts.LenBits, opts.LenBits, target)
+			g.printf("if err := buf.WriteUnsignedBitInt32(uint32(len(%s)), %d); err != nil {\nreturn err\n}\n", target, opts.LenBits)
+		} else if opts.Vec3Coord {
+			if length != 3 || types.ExprString(e.Elt) != "float32" {
+				return fmt.Errorf("vec3coord requires [3]float32, not %s", types.ExprString(e))
+			}
+			g.printf("if err := buf.WriteBitVec3Coord(%s); err != nil {\nreturn err\n}\n", target)
+			return nil
+		}
+		g.printf("for %s := range %s {\n", index, target)
+		if err := g.encodeValue(target+"["+index+"]", e.Elt, opts, depth+1); err != nil {
+			return err
+		}
+		g.printf("}\n")
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+// encodeString writes a null-terminated string. As with Marshal, a string that
+// does not fit in max bytes along with its terminator is an error.
+func (g *generator) encodeString(target string, opts tag.Options) {
+	if opts.Max != 0 {
+		g.printf("if len(%s) >= %d {\nreturn bitbuf.ErrStringTruncated\n}\n", target, opts.Max)
+	}
+	g.printf("if err := buf.WriteString(%s + \"\\x00\"); err != nil {\nreturn err\n}\n", target)
+}
+
+// encodeCall returns the Writer method call that encodes a numeric type
+func (g *generator) encodeCall(target string, basic basicType, opts tag.Options) (string, error) {
+	switch basic.class {
+	case "bool":
+		return fmt.Sprintf("WriteOneBit(%s)", target), nil
+	case "float":
+		switch {
+		case opts.Coord:
+			return fmt.Sprintf("WriteBitCoord(float32(%s))", target), nil
+		case opts.Normal:
+			return fmt.Sprintf("WriteBitNormal(float32(%s))", target), nil
+		case opts.Angle != 0:
+			return fmt.Sprintf("WriteBitAngle(float32(%s), %d)", target, opts.Angle), nil
+		case basic.bits == 32:
+			return fmt.Sprintf("WriteFloat32(%s)", target), nil
+		default:
+			return fmt.Sprintf("WriteFloat64(%s)", target), nil
+		}
+	}
+
+	if opts.VarInt {
+		switch {
+		case basic.bits > 32 && opts.Signed:
+			return fmt.Sprintf("WriteSignedVarInt64(int64(%s))", target), nil
+		case basic.bits > 32:
+			return fmt.Sprintf("WriteVarInt64(uint64(%s))", target), nil
+		case opts.Signed:
+			return fmt.Sprintf("WriteSignedVarInt32(int32(%s))", target), nil
+		default:
+			return fmt.Sprintf("WriteVarInt32(uint32(%s))", target), nil
+		}
+	}
+	numBits := basic.bits
+	if opts.Bits != 0 {
+		numBits = opts.Bits
+	}
+	if numBits > basic.bits {
+		return "", fmt.Errorf("%d bits do not fit in a %d bit integer", numBits, basic.bits)
+	}
+	if basic.platform && opts.Bits == 0 {
+		g.imports["math/bits"] = true
+		return fmt.Sprintf("WriteUnsignedBitInt64(uint64(%s), bits.UintSize)", target), nil
+	}
+	if numBits > 32 {
+		return fmt.Sprintf("WriteUnsignedBitInt64(uint64(%s), %d)", target, numBits), nil
+	}
+	return fmt.Sprintf("WriteUnsignedBitInt32(uint32(%s), %d)", target, numBits), nil
+}
+
+// roundTripTest generates a test that encodes a sample value, checks the output
+// matches bitbuf.Marshal, and decodes it back again.
+func (g *generator) roundTripTest(typeName string) error {
+	sample, err := g.sampleValue(&ast.Ident{Name: typeName}, tag.Options{}, 0)
+	if err != nil {
+		return err
+	}
+	g.printf("func Test%s_BitsRoundTrip(t *testing.T) {\n", typeName)
+	g.printf("expected := %s\n\n", sample)
+	g.printf("writer := bitbuf.NewGrowableWriter(0, 0)\nif err := expected.EncodeBits(writer); err != nil {\nt.Fatal(err)\n}\n\n")
+	g.printf("reflected := bitbuf.NewGrowableWriter(0, 0)\nif err := bitbuf.Marshal(reflected, &expected); err != nil {\nt.Fatal(err)\n}\n")
+	g.printf("if !bytes.Equal(writer.Data(), reflected.Data()) {\nt.Errorf(\"expected: %%v, but received: %%v\", reflected.Data(), writer.Data())\n}\n\n")
+	g.printf("var actual %s\nreader := bitbuf.NewReader(writer.Data())\nif err := actual.DecodeBits(reader); err != nil {\nt.Fatal(err)\n}\n", typeName)
+	g.printf("if !reflect.DeepEqual(actual, expected) {\nt.Errorf(\"expected: %%+v, but received: %%+v\", expected, actual)\n}\n")
+	g.printf("if reader.BitsRead() != writer.BitsWritten() {\nt.Errorf(\"expected: %%d bits read, but received: %%d\", writer.BitsWritten(), reader.BitsRead())\n}\n}\n\n")
+	return nil
+}
+
+// sampleValue returns a Go expression for a value of the given type that survives
+// a round trip through its encoding unchanged.
+func (g *generator) sampleValue(expr ast.Expr, opts tag.Options, depth int) (string, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if _, ok := g.pkg.structs[e.Name]; ok {
+			fields, err := g.pkg.fields(e.Name)
+			if err != nil {
+				return "", err
+			}
+			values := make([]string, 0)
+			for _, field := range fields {
+				value, err := g.sampleValue(field.expr, field.opts, depth+1)
+				if err != nil {
+					return "", err
+				}
+				values = append(values, fmt.Sprintf("%s: %s", field.name, value))
+			}
+			return fmt.Sprintf("%s{%s}", e.Name, strings.Join(values, ", ")), nil
+		}
+		if underlying := g.pkg.underlying(e.Name); underlying != nil {
+			return g.sampleValue(underlying, opts, depth)
+		}
+		_, basic, ok := g.pkg.basicOf(e.Name)
+		if !ok {
+			return "", fmt.Errorf("unsupported type %s", e.Name)
+		}
+		return sampleBasic(e.Name, basic, opts), nil
+	case *ast.ArrayType:
+		length, err := arrayLength(e)
+		if err != nil {
+			return "", err
+		}
+		if opts.Vec3Coord {
+			return "[3]float32{1.5, 0, -2.25}", nil
+		}
+		if length < 0 {
+			length = 2
+			if opts.LenBits == 1 {
+				length = 1
+			}
+			if depth >= maxSampleDepth {
+				length = 0
+			}
+		}
+		element, err := g.sampleValue(e.Elt, opts, depth+1)
+		if err != nil {
+			return "", err
+		}
+		elements := make([]string, length)
+		for i := range elements {
+			elements[i] = element
+		}
+		return fmt.Sprintf("%s{%s}", types.ExprString(e), strings.Join(elements, ", ")), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", types.ExprString(expr))
+}
+
+func sampleBasic(typeName string, basic basicType, opts tag.Options) string {
+	switch basic.class {
+	case "bool":
+		return "true"
+	case "string":
+		value := "bitbuf"
+		if opts.Max != 0 && uint(len(value)) >= opts.Max {
+			value = value[:opts.Max-1]
+		}
+		return strconv.Quote(value)
+	case "float":
+		switch {
+		case opts.Coord:
+			return typeName + "(-1024.5)"
+		case opts.Normal:
+			return typeName + "(-1)"
+		case opts.Angle == 1:
+			return typeName + "(180)"
+		case opts.Angle != 0:
+			return typeName + "(90)"
+		default:
+			return typeName + "(2106.25)"
+		}
+	}
+
+	if opts.VarInt {
+		if opts.Signed {
+			return typeName + "(-300)"
+		}
+		return typeName + "(300)"
+	}
+	numBits := basic.bits
+	if opts.Bits != 0 {
+		numBits = opts.Bits
+	}
+	if basic.platform && numBits > 32 {
+		// The sample must also fit on 32 bit platforms
+		numBits = 32
+	}
+	switch {
+	case opts.Signed:
+		// The most negative value representable in numBits
+		return fmt.Sprintf("%s(%d)", typeName, int64(-1)<<(numBits-1))
+	case basic.class == "int" && numBits == basic.bits:
+		return fmt.Sprintf("%s(%d)", typeName, int64(-1)<<(numBits-2))
+	case basic.class == "int":
+		// Unsigned reads into a signed type must not set the sign bit
+		numBits--
+	}
+	if numBits == 0 {
+		return typeName + "(0)"
+	}
+	return fmt.Sprintf("%s(%d)", typeName, uint64(0x5A5A5A5A5A5A5A5A)>>(64-numBits))
+}
@@ -0,0 +1,72 @@
+// Command bitbufgen generates reflection free DecodeBits and EncodeBits methods
+// for structs annotated with `bitbuf` tags, along with round trip tests.
+//
+// It is intended to be run via go:generate:
+//
+//	//go:generate bitbufgen -type ServerInfo,ClassInfo
+//
+// Tags are interpreted exactly as by bitbuf.Marshal and bitbuf.Unmarshal,
+// and nested struct types are generated automatically.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of struct type names; required")
+	output    = flag.String("output", "", "output file name; default <dir>/<type>_bitbuf.go")
+	tests     = flag.Bool("tests", true, "also generate round trip tests, in <output>_test.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of bitbufgen:\n")
+	fmt.Fprintf(os.Stderr, "\tbitbufgen [flags] -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("bitbufgen: ")
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	pkg, err := parsePackage(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	source, testSource, err := generate(pkg, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputName := *output
+	if outputName == "" {
+		outputName = filepath.Join(dir, strings.ToLower(types[0])+"_bitbuf.go")
+	}
+	if err := os.WriteFile(outputName, source, 0644); err != nil {
+		log.Fatal(err)
+	}
+	if *tests {
+		testName := strings.TrimSuffix(outputName, ".go") + "_test.go"
+		if err := os.WriteFile(testName, testSource, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"os"
+	"testing"
+)
+
+// TestGenerate_Golden checks the committed example output is what the generator produces.
+// Run go generate ./example/... to update it.
+func TestGenerate_Golden(t *testing.T) {
+	pkg, err := parsePackage("../../example/messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, testSource, err := generate(pkg, []string{"ServerInfo", "PlayerUpdate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, actual := range map[string][]byte{
+		"../../example/messages/messages_bitbuf.go":      source,
+		"../../example/messages/messages_bitbuf_test.go": testSource,
+	} {
+		expected, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("generated source differs from %s", name)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	pkg := &pkg{name: "test", structs: map[string]*ast.StructType{}}
+	if _, _, err := generate(pkg, []string{"Missing"}); err == nil {
+		t.Error("expected an error for a missing type, but received none")
+	}
+}
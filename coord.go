@@ -0,0 +1,281 @@
+package bitbuf
+
+import "math"
+
+// Source engine coordinate encoding constants.
+// These must match the engine exactly for encoded values to be bit-compatible.
+const (
+	// CoordIntegerBits is the number of bits used for the integer part of a coord
+	CoordIntegerBits = 14
+	// CoordFractionalBits is the number of bits used for the fractional part of a coord
+	CoordFractionalBits = 5
+	// CoordDenominator is the fractional denominator of a coord
+	CoordDenominator = 1 << CoordFractionalBits
+	// CoordResolution is the smallest representable coord fraction
+	CoordResolution = 1.0 / CoordDenominator
+
+	// CoordIntegerBitsMP is the number of bits used for the integer part of an in-bounds multiplayer coord
+	CoordIntegerBitsMP = 11
+	// CoordFractionalBitsMPLowPrecision is the number of bits used for the fractional part of a low precision coord
+	CoordFractionalBitsMPLowPrecision = 3
+	// CoordDenominatorLowPrecision is the fractional denominator of a low precision coord
+	CoordDenominatorLowPrecision = 1 << CoordFractionalBitsMPLowPrecision
+	// CoordResolutionLowPrecision is the smallest representable low precision coord fraction
+	CoordResolutionLowPrecision = 1.0 / CoordDenominatorLowPrecision
+)
+
+// ReadBitCoord reads a coordinate, encoded as optional integer and fractional parts
+func (buf *Reader) ReadBitCoord() (float32, error) {
+	hasInt, err := buf.ReadOneBit()
+	if err != nil {
+		return 0, err
+	}
+	hasFract, err := buf.ReadOneBit()
+	if err != nil {
+		return 0, err
+	}
+	// Neither part means the value is 0
+	if !hasInt && !hasFract {
+		return 0, nil
+	}
+
+	negative, err := buf.ReadOneBit()
+	if err != nil {
+		return 0, err
+	}
+	intVal := uint32(0)
+	if hasInt {
+		// Integers are sent as [0..MAX_COORD_VALUE-1], but represent [1..MAX_COORD_VALUE]
+		if intVal, err = buf.ReadUint32Bits(CoordIntegerBits); err != nil {
+			return 0, err
+		}
+		intVal++
+	}
+	fractVal := uint32(0)
+	if hasFract {
+		if fractVal, err = buf.ReadUint32Bits(CoordFractionalBits); err != nil {
+			return 0, err
+		}
+	}
+
+	value := float32(intVal) + float32(fractVal)*CoordResolution
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// ReadBitCoordMP reads a multiplayer coordinate.
+// integral coords have no fractional part, and lowPrecision coords have a smaller fractional part.
+func (buf *Reader) ReadBitCoordMP(integral bool, lowPrecision bool) (float32, error) {
+	inBounds, err := buf.ReadOneBit()
+	if err != nil {
+		return 0, err
+	}
+	intBits := uint(CoordIntegerBits)
+	if inBounds {
+		intBits = CoordIntegerBitsMP
+	}
+
+	hasInt, err := buf.ReadOneBit()
+	if err != nil {
+		return 0, err
+	}
+
+	negative := false
+	value := float32(0)
+	if integral {
+		if hasInt {
+			if negative, err = buf.ReadOneBit(); err != nil {
+				return 0, err
+			}
+			intVal, err := buf.ReadUint32Bits(intBits)
+			if err != nil {
+				return 0, err
+			}
+			value = float32(intVal + 1)
+		}
+	} else {
+		if negative, err = buf.ReadOneBit(); err != nil {
+			return 0, err
+		}
+		intVal := uint32(0)
+		if hasInt {
+			if intVal, err = buf.ReadUint32Bits(intBits); err != nil {
+				return 0, err
+			}
+			intVal++
+		}
+
+		fractBits, resolution := uint(CoordFractionalBits), float32(CoordResolution)
+		if lowPrecision {
+			fractBits, resolution = CoordFractionalBitsMPLowPrecision, CoordResolutionLowPrecision
+		}
+		fractVal, err := buf.ReadUint32Bits(fractBits)
+		if err != nil {
+			return 0, err
+		}
+		value = float32(intVal) + float32(fractVal)*resolution
+	}
+
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// ReadBitCellCoord reads a coordinate relative to a cell, with an integer part of numBits.
+// integral coords have no fractional part, and lowPrecision coords have a smaller fractional part.
+func (buf *Reader) ReadBitCellCoord(numBits uint, integral bool, lowPrecision bool) (float32, error) {
+	intVal, err := buf.ReadUint32Bits(numBits)
+	if err != nil {
+		return 0, err
+	}
+	if integral {
+		return float32(intVal), nil
+	}
+
+	fractBits, resolution := uint(CoordFractionalBits), float32(CoordResolution)
+	if lowPrecision {
+		fractBits, resolution = CoordFractionalBitsMPLowPrecision, CoordResolutionLowPrecision
+	}
+	fractVal, err := buf.ReadUint32Bits(fractBits)
+	if err != nil {
+		return 0, err
+	}
+	return float32(intVal) + float32(fractVal)*resolution, nil
+}
+
+// ReadBitVec3Coord reads a vector of 3 coords. Components that are 0 are omitted from the stream.
+func (buf *Reader) ReadBitVec3Coord() (vec [3]float32, err error) {
+	var present [3]bool
+	for i := range present {
+		if present[i], err = buf.ReadOneBit(); err != nil {
+			return vec, err
+		}
+	}
+	for i := range vec {
+		if !present[i] {
+			continue
+		}
+		if vec[i], err = buf.ReadBitCoord(); err != nil {
+			return vec, err
+		}
+	}
+	return vec, nil
+}
+
+// WriteBitCoord writes a coordinate, encoded as optional integer and fractional parts
+func (writer *Writer) WriteBitCoord(value float32) error {
+	negative := value <= -CoordResolution
+	intVal := uint32(math.Abs(float64(value)))
+	fractVal := uint32(absInt32(int32(value*CoordDenominator))) & (CoordDenominator - 1)
+
+	if err := writer.WriteOneBit(intVal != 0); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(fractVal != 0); err != nil {
+		return err
+	}
+	if intVal == 0 && fractVal == 0 {
+		return nil
+	}
+
+	if err := writer.WriteOneBit(negative); err != nil {
+		return err
+	}
+	if intVal != 0 {
+		// Integers represent [1..MAX_COORD_VALUE], but are sent as [0..MAX_COORD_VALUE-1]
+		if err := writer.WriteUnsignedBitInt32(intVal-1, CoordIntegerBits); err != nil {
+			return err
+		}
+	}
+	if fractVal != 0 {
+		return writer.WriteUnsignedBitInt32(fractVal, CoordFractionalBits)
+	}
+	return nil
+}
+
+// WriteBitCoordMP writes a multiplayer coordinate.
+// integral coords have no fractional part, and lowPrecision coords have a smaller fractional part.
+func (writer *Writer) WriteBitCoordMP(value float32, integral bool, lowPrecision bool) error {
+	fractBits, resolution, denominator := uint(CoordFractionalBits), float32(CoordResolution), float32(CoordDenominator)
+	if lowPrecision {
+		fractBits, resolution, denominator = CoordFractionalBitsMPLowPrecision, CoordResolutionLowPrecision, CoordDenominatorLowPrecision
+	}
+	negative := value <= -resolution
+	intVal := uint32(math.Abs(float64(value)))
+	fractVal := uint32(absInt32(int32(value*denominator))) & uint32(denominator-1)
+
+	inBounds := intVal < 1<<CoordIntegerBitsMP
+	intBits := uint(CoordIntegerBits)
+	if inBounds {
+		intBits = CoordIntegerBitsMP
+	}
+
+	if err := writer.WriteOneBit(inBounds); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(intVal != 0); err != nil {
+		return err
+	}
+
+	if integral {
+		if intVal == 0 {
+			return nil
+		}
+		if err := writer.WriteOneBit(negative); err != nil {
+			return err
+		}
+		return writer.WriteUnsignedBitInt32(intVal-1, intBits)
+	}
+
+	if err := writer.WriteOneBit(negative); err != nil {
+		return err
+	}
+	if intVal != 0 {
+		if err := writer.WriteUnsignedBitInt32(intVal-1, intBits); err != nil {
+			return err
+		}
+	}
+	return writer.WriteUnsignedBitInt32(fractVal, fractBits)
+}
+
+// WriteBitCellCoord writes a coordinate relative to a cell, with an integer part of numBits.
+// integral coords have no fractional part, and lowPrecision coords have a smaller fractional part.
+func (writer *Writer) WriteBitCellCoord(value float32, numBits uint, integral bool, lowPrecision bool) error {
+	fractBits, denominator := uint(CoordFractionalBits), float32(CoordDenominator)
+	if lowPrecision {
+		fractBits, denominator = CoordFractionalBitsMPLowPrecision, CoordDenominatorLowPrecision
+	}
+	intVal := uint32(math.Abs(float64(value)))
+	fractVal := uint32(absInt32(int32(value*denominator))) & uint32(denominator-1)
+
+	if err := writer.WriteUnsignedBitInt32(intVal, numBits); err != nil {
+		return err
+	}
+	if integral {
+		return nil
+	}
+	return writer.WriteUnsignedBitInt32(fractVal, fractBits)
+}
+
+// WriteBitVec3Coord writes a vector of 3 coords. Components that are 0 are omitted from the stream.
+func (writer *Writer) WriteBitVec3Coord(vec [3]float32) error {
+	var present [3]bool
+	for i := range vec {
+		present[i] = vec[i] >= CoordResolution || vec[i] <= -CoordResolution
+		if err := writer.WriteOneBit(present[i]); err != nil {
+			return err
+		}
+	}
+	for i := range vec {
+		if !present[i] {
+			continue
+		}
+		if err := writer.WriteBitCoord(vec[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package bitbuf
+
+import (
+	"testing"
+)
+
+func TestWriter_WriteBitCoord(t *testing.T) {
+	sut := NewWriter(8)
+
+	if err := sut.WriteBitCoord(1.5); err != nil {
+		t.Error(err)
+	}
+
+	// int flag, fract flag, positive, integer 1 (sent as 0), fraction 16/32
+	expected := uint32(1<<21 | 3)
+	if sut.BitsWritten() != 22 {
+		t.Errorf("expected: %d bits written, but received: %d", 22, sut.BitsWritten())
+	}
+	if val, err := NewReader(sut.Data()).ReadUint32Bits(22); err != nil || val != expected {
+		t.Errorf("expected: %b, but received: %b (%v)", expected, val, err)
+	}
+}
+
+func TestReader_ReadBitCoord(t *testing.T) {
+	values := []float32{0, 1, -1, 1.5, -0.03125, 0.96875, 1234.25, -16383.5, 16384}
+	sut := NewWriter(64)
+	for _, v := range values {
+		if err := sut.WriteBitCoord(v); err != nil {
+			t.Error(err)
+		}
+	}
+
+	reader := NewReader(sut.Data())
+	for _, expected := range values {
+		if val, err := reader.ReadBitCoord(); err != nil || val != expected {
+			t.Errorf("expected: %f, but received: %f (%v)", expected, val, err)
+		}
+	}
+	if reader.BitsRead() != sut.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", sut.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestReader_ReadBitCoordMP(t *testing.T) {
+	testCases := []struct {
+		integral     bool
+		lowPrecision bool
+		values       []float32
+	}{
+		{false, false, []float32{0, 1.5, -2047.96875, 2048.5, -12000.25}},
+		{false, true, []float32{0, 1.5, -2047.875, 2048.125, -12000.25}},
+		{true, false, []float32{0, 1, -2047, 2048, -12000}},
+	}
+
+	for _, tc := range testCases {
+		sut := NewWriter(64)
+		for _, v := range tc.values {
+			if err := sut.WriteBitCoordMP(v, tc.integral, tc.lowPrecision); err != nil {
+				t.Error(err)
+			}
+		}
+
+		reader := NewReader(sut.Data())
+		for _, expected := range tc.values {
+			if val, err := reader.ReadBitCoordMP(tc.integral, tc.lowPrecision); err != nil || val != expected {
+				t.Errorf("expected: %f, but received: %f (%v)", expected, val, err)
+			}
+		}
+		if reader.BitsRead() != sut.BitsWritten() {
+			t.Errorf("expected: %d bits read, but received: %d", sut.BitsWritten(), reader.BitsRead())
+		}
+	}
+}
+
+func TestReader_ReadBitCellCoord(t *testing.T) {
+	testCases := []struct {
+		numBits      uint
+		integral     bool
+		lowPrecision bool
+		value        float32
+	}{
+		{8, false, false, 127.40625},
+		{8, false, true, 127.375},
+		{10, true, false, 1000},
+	}
+
+	for _, tc := range testCases {
+		sut := NewWriter(8)
+		if err := sut.WriteBitCellCoord(tc.value, tc.numBits, tc.integral, tc.lowPrecision); err != nil {
+			t.Error(err)
+		}
+		if val, err := NewReader(sut.Data()).ReadBitCellCoord(tc.numBits, tc.integral, tc.lowPrecision); err != nil || val != tc.value {
+			t.Errorf("expected: %f, but received: %f (%v)", tc.value, val, err)
+		}
+	}
+}
+
+func TestReader_ReadBitVec3Coord(t *testing.T) {
+	expected := [3]float32{-1024.5, 0, 3.25}
+	sut := NewWriter(16)
+	if err := sut.WriteBitVec3Coord(expected); err != nil {
+		t.Error(err)
+	}
+
+	if val, err := NewReader(sut.Data()).ReadBitVec3Coord(); err != nil || val != expected {
+		t.Errorf("expected: %v, but received: %v (%v)", expected, val, err)
+	}
+}
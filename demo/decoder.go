@@ -0,0 +1,143 @@
+package demo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/galaco/bitbuf"
+)
+
+// MaxPayloadLength is the largest frame payload a Decoder will accept, in bytes.
+// It guards against allocating huge buffers when reading corrupt demos.
+const MaxPayloadLength = 64 << 20
+
+// Decoder reads the frames of a demo in order.
+//
+//	decoder, err := demo.NewDecoder(file)
+//	...
+//	for decoder.Next() {
+//		frame := decoder.Frame()
+//		...
+//	}
+//	if err := decoder.Err(); err != nil {
+//		...
+//	}
+type Decoder struct {
+	buf    *bitbuf.Reader
+	header *Header
+	slots  int
+
+	frame   Frame
+	err     error
+	stopped bool
+}
+
+// Header returns the demo header
+func (decoder *Decoder) Header() *Header {
+	return decoder.header
+}
+
+// SetSplitScreenSlots sets the number of CmdInfo entries in each signon and packet frame.
+// Source 2013 demos have 1, which is the default. CS:GO demos have 2.
+func (decoder *Decoder) SetSplitScreenSlots(slots int) {
+	decoder.slots = slots
+}
+
+// Next reads the next frame, returning false once the demo ends or an error occurs.
+// The frame returned by Frame is overwritten by the next call to Next.
+// A demo that ends cleanly between frames, without a dem_stop, is not treated as an error.
+func (decoder *Decoder) Next() bool {
+	if decoder.err != nil || decoder.stopped {
+		return false
+	}
+	err := decoder.readFrame()
+	if errors.Is(err, io.EOF) {
+		return false
+	}
+	if err != nil {
+		decoder.err = fmt.Errorf("demo frame at byte %d: %w", decoder.buf.BitsRead()>>3, err)
+		return false
+	}
+	decoder.stopped = decoder.frame.Command == CommandStop
+	return true
+}
+
+// Frame returns the frame read by the last call to Next
+func (decoder *Decoder) Frame() *Frame {
+	return &decoder.frame
+}
+
+// Err returns the first error encountered, if any
+func (decoder *Decoder) Err() error {
+	return decoder.err
+}
+
+func (decoder *Decoder) readFrame() error {
+	buf := decoder.buf
+	raw, err := buf.ReadUint8()
+	if err != nil {
+		// A stream that ends here ends between frames, which returns io.EOF
+		return err
+	}
+	cmd, err := decodeCommand(raw, decoder.header.DemoProtocol)
+	if err != nil {
+		return err
+	}
+
+	decoder.frame = Frame{Command: cmd}
+	frame := &decoder.frame
+	frame.Tick, _ = buf.ReadInt32()
+	if decoder.header.DemoProtocol >= protocolWithCustomData {
+		frame.PlayerSlot, _ = buf.ReadUint8()
+	}
+
+	switch cmd {
+	case CommandSignon, CommandPacket:
+		packet := &PacketInfo{CmdInfo: make([]CmdInfo, decoder.slots)}
+		for i := range packet.CmdInfo {
+			packet.CmdInfo[i] = readCmdInfo(buf)
+		}
+		packet.SequenceIn, _ = buf.ReadInt32()
+		packet.SequenceOut, _ = buf.ReadInt32()
+		frame.Packet = packet
+	case CommandUserCmd:
+		frame.OutgoingSequence, _ = buf.ReadInt32()
+	case CommandCustomData:
+		frame.CustomDataType, _ = buf.ReadInt32()
+	}
+
+	var payload []byte
+	if cmd.hasPayload() {
+		length, _ := buf.ReadInt32()
+		if buf.Err() == nil && (length < 0 || length > MaxPayloadLength) {
+			return fmt.Errorf("demo %s payload length %d out of range", cmd, length)
+		}
+		payload, _ = buf.ReadBytes(uint(length))
+	}
+	if err := buf.Err(); err != nil {
+		// The frame was cut short, so the end of the stream is unexpected
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	frame.Data = bitbuf.NewReader(payload)
+	return nil
+}
+
+// NewDecoder returns a Decoder that reads a demo from source, after reading its header.
+func NewDecoder(source io.Reader) (*Decoder, error) {
+	buf := bitbuf.NewStreamReader(source)
+	buf.SetStickyErrors(true)
+
+	header, err := readHeader(buf)
+	if err != nil {
+		return nil, err
+	}
+	return &Decoder{
+		buf:    buf,
+		header: header,
+		slots:  1,
+	}, nil
+}
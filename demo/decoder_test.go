@@ -0,0 +1,160 @@
+package demo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	for name, demoProtocol := range map[string]int32{"testdata/protocol3.dem": 3, "testdata/protocol4.dem": 4} {
+		file, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		sut, err := NewDecoder(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sut.Header(), syntheticHeader(demoProtocol)) {
+			t.Errorf("expected: %+v, but received: %+v", syntheticHeader(demoProtocol), sut.Header())
+		}
+
+		expected := syntheticFrames(demoProtocol)
+		i := 0
+		for ; sut.Next(); i++ {
+			if i >= len(expected) {
+				t.Fatalf("expected: %d frames, but received more", len(expected))
+			}
+			frame := *sut.Frame()
+			payload := frame.Data.Data()
+			frame.Data = nil
+			if demoProtocol < protocolWithCustomData {
+				expected[i].frame.PlayerSlot = 0
+			}
+			if !reflect.DeepEqual(frame, expected[i].frame) {
+				t.Errorf("%s frame %d. expected: %+v, but received: %+v", name, i, expected[i].frame, frame)
+			}
+			if !bytes.Equal(payload, expected[i].payload) {
+				t.Errorf("%s frame %d. expected: %v, but received: %v", name, i, expected[i].payload, payload)
+			}
+		}
+		if err := sut.Err(); err != nil {
+			t.Error(err)
+		}
+		if i != len(expected) {
+			t.Errorf("expected: %d frames, but received: %d", len(expected), i)
+		}
+	}
+}
+
+func TestDecoder_PacketPayload(t *testing.T) {
+	sut, err := NewDecoder(bytes.NewReader(syntheticDemo(t, 4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for sut.Next() && sut.Frame().Command != CommandPacket {
+	}
+
+	payload := sut.Frame().Data
+	if msgType, err := payload.ReadUint32Bits(6); err != nil || msgType != 4 {
+		t.Errorf("expected: %d, but received: %d (%v)", 4, msgType, err)
+	}
+	if _, err := payload.ReadInt32(); err != nil {
+		t.Error(err)
+	}
+	if val, err := payload.ReadCString(0); err != nil || val != "sv_cheats 0" {
+		t.Errorf("expected: %s, but received: %s (%v)", "sv_cheats 0", val, err)
+	}
+}
+
+func TestDecoder_WithoutStop(t *testing.T) {
+	data := syntheticDemo(t, 4)
+	// Drop the trailing dem_stop frame
+	sut, err := NewDecoder(bytes.NewReader(data[:len(data)-6]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := 0
+	for sut.Next() {
+		frames++
+	}
+	if sut.Err() != nil {
+		t.Error(sut.Err())
+	}
+	if frames != len(syntheticFrames(4))-1 {
+		t.Errorf("expected: %d frames, but received: %d", len(syntheticFrames(4))-1, frames)
+	}
+}
+
+func TestDecoder_Truncated(t *testing.T) {
+	data := syntheticDemo(t, 4)
+	for _, length := range []int{HeaderSize + 3, HeaderSize + 20, len(data) - 10} {
+		sut, err := NewDecoder(bytes.NewReader(data[:length]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for sut.Next() {
+		}
+		if !errors.Is(sut.Err(), io.ErrUnexpectedEOF) {
+			t.Errorf("expected: %v, but received: %v", io.ErrUnexpectedEOF, sut.Err())
+		}
+	}
+
+	if _, err := NewDecoder(bytes.NewReader(data[:HeaderSize-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected: %v, but received: %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestDecoder_InvalidFilestamp(t *testing.T) {
+	data := syntheticDemo(t, 4)
+	copy(data, "HL2DEMX")
+	if _, err := NewDecoder(bytes.NewReader(data)); !errors.Is(err, ErrInvalidFilestamp) {
+		t.Errorf("expected: %v, but received: %v", ErrInvalidFilestamp, err)
+	}
+}
+
+func TestDecoder_UnknownCommand(t *testing.T) {
+	data := syntheticDemo(t, 3)
+	// Protocol 3 demos have no dem_stringtables with value 9
+	data[HeaderSize] = 9
+	sut, err := NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sut.Next() || sut.Err() == nil {
+		t.Error("expected an error decoding an unknown command, but received none")
+	}
+}
+
+func TestDecoder_SplitScreenSlots(t *testing.T) {
+	sink := &bytes.Buffer{}
+	encoder, err := NewEncoder(sink, syntheticHeader(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	packet := &PacketInfo{CmdInfo: []CmdInfo{{Flags: 1}, {Flags: 2}}, SequenceIn: 3, SequenceOut: 4}
+	if err := encoder.WriteFrame(&Frame{Command: CommandPacket, Packet: packet}, []byte{9}); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	sut, err := NewDecoder(sink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sut.SetSplitScreenSlots(2)
+	if !sut.Next() {
+		t.Fatal(sut.Err())
+	}
+	if !reflect.DeepEqual(sut.Frame().Packet, packet) {
+		t.Errorf("expected: %+v, but received: %+v", packet, sut.Frame().Packet)
+	}
+}
@@ -0,0 +1,92 @@
+package demo
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/galaco/bitbuf"
+)
+
+// Encoder writes a demo frame by frame. It is the inverse of Decoder, and is
+// mostly useful for producing test fixtures.
+type Encoder struct {
+	writer *bitbuf.Writer
+	header Header
+}
+
+// WriteFrame writes a frame, followed by payload for frames that have one.
+// frame.Data is ignored. Signon and packet frames must have frame.Packet set.
+func (encoder *Encoder) WriteFrame(frame *Frame, payload []byte) error {
+	raw, err := encodeCommand(frame.Command, encoder.header.DemoProtocol)
+	if err != nil {
+		return err
+	}
+
+	writer := encoder.writer
+	if err := writer.WriteUint8(raw); err != nil {
+		return err
+	}
+	if err := writer.WriteInt32(frame.Tick); err != nil {
+		return err
+	}
+	if encoder.header.DemoProtocol >= protocolWithCustomData {
+		if err := writer.WriteUint8(frame.PlayerSlot); err != nil {
+			return err
+		}
+	}
+
+	switch frame.Command {
+	case CommandSignon, CommandPacket:
+		if frame.Packet == nil {
+			return fmt.Errorf("demo %s frame requires packet info", frame.Command)
+		}
+		for i := range frame.Packet.CmdInfo {
+			if err := writeCmdInfo(writer, &frame.Packet.CmdInfo[i]); err != nil {
+				return err
+			}
+		}
+		if err := writer.WriteInt32(frame.Packet.SequenceIn); err != nil {
+			return err
+		}
+		if err := writer.WriteInt32(frame.Packet.SequenceOut); err != nil {
+			return err
+		}
+	case CommandUserCmd:
+		if err := writer.WriteInt32(frame.OutgoingSequence); err != nil {
+			return err
+		}
+	case CommandCustomData:
+		if err := writer.WriteInt32(frame.CustomDataType); err != nil {
+			return err
+		}
+	}
+
+	if frame.Command.hasPayload() {
+		if err := writer.WriteInt32(int32(len(payload))); err != nil {
+			return err
+		}
+		if err := writer.WriteBytes(payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the sink
+func (encoder *Encoder) Flush() error {
+	return encoder.writer.Flush()
+}
+
+// NewEncoder returns an Encoder that writes a demo to sink, after writing header.
+// header.Filestamp is ignored. Call Flush once all frames are written.
+func NewEncoder(sink io.Writer, header *Header) (*Encoder, error) {
+	writer := bitbuf.NewStreamWriter(sink)
+	writer.SetStickyErrors(true)
+	if err := writeHeader(writer, header); err != nil {
+		return nil, err
+	}
+	return &Encoder{
+		writer: writer,
+		header: *header,
+	}, nil
+}
@@ -0,0 +1,169 @@
+package demo
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+)
+
+var update = flag.Bool("update", false, "update the fixture demos in testdata")
+
+// syntheticHeader returns the header of the fixture demos
+func syntheticHeader(demoProtocol int32) *Header {
+	return &Header{
+		Filestamp:       Filestamp,
+		DemoProtocol:    demoProtocol,
+		NetworkProtocol: 24,
+		ServerName:      "localhost:27015",
+		ClientName:      "bitbuf",
+		MapName:         "de_synthetic",
+		GameDirectory:   "cstrike",
+		PlaybackTime:    0.09375,
+		PlaybackTicks:   6,
+		PlaybackFrames:  3,
+		SignonLength:    178,
+	}
+}
+
+// syntheticFrame is a frame of a fixture demo, along with its payload
+type syntheticFrame struct {
+	frame   Frame
+	payload []byte
+}
+
+// syntheticFrames returns the frames of the fixture demos. Payloads are produced
+// with a bitbuf.Writer, as real payloads are bitstreams that are rarely byte aligned.
+func syntheticFrames(demoProtocol int32) []syntheticFrame {
+	messages := bitbuf.NewGrowableWriter(0, 0)
+	messages.WriteUnsignedBitInt32(4, 6)
+	messages.WriteInt32(5)
+	messages.WriteCString("sv_cheats 0")
+	messages.WriteOneBit(true)
+
+	cmdInfo := CmdInfo{
+		Flags:      1,
+		ViewOrigin: [3]float32{-1024, 512.5, 64.03125},
+		ViewAngles: [3]float32{10, 270, 0},
+	}
+	frames := []syntheticFrame{
+		{Frame{Command: CommandSignon, Packet: &PacketInfo{CmdInfo: []CmdInfo{{}}, SequenceIn: 1, SequenceOut: 2}}, messages.Data()},
+		{Frame{Command: CommandDataTables}, []byte{1, 2, 3, 4, 5}},
+		{Frame{Command: CommandStringTables}, []byte{0}},
+		{Frame{Command: CommandSyncTick}, nil},
+		{Frame{Command: CommandPacket, Tick: 2, Packet: &PacketInfo{CmdInfo: []CmdInfo{cmdInfo}, SequenceIn: 3, SequenceOut: 4}}, messages.Data()},
+		{Frame{Command: CommandConsoleCmd, Tick: 4}, []byte("status\x00")},
+		{Frame{Command: CommandUserCmd, Tick: 4, OutgoingSequence: 5}, []byte{}},
+	}
+	if demoProtocol >= protocolWithCustomData {
+		frames = append(frames, syntheticFrame{Frame{Command: CommandCustomData, Tick: 5, PlayerSlot: 1, CustomDataType: 7}, []byte("custom")})
+	}
+	return append(frames, syntheticFrame{Frame{Command: CommandStop, Tick: 6}, nil})
+}
+
+// syntheticDemo encodes a fixture demo
+func syntheticDemo(t *testing.T, demoProtocol int32) []byte {
+	sink := &bytes.Buffer{}
+	sut, err := NewEncoder(sink, syntheticHeader(demoProtocol))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range syntheticFrames(demoProtocol) {
+		if err := sut.WriteFrame(&f.frame, f.payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sut.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return sink.Bytes()
+}
+
+func TestEncoder_Fixtures(t *testing.T) {
+	fixtures := map[string]int32{
+		"protocol3.dem": 3,
+		"protocol4.dem": 4,
+	}
+	for name, demoProtocol := range fixtures {
+		path := filepath.Join("testdata", name)
+		actual := syntheticDemo(t, demoProtocol)
+		if *update {
+			if err := os.WriteFile(path, actual, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expected, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("encoded demo differs from %s; run go test -update to regenerate it", path)
+		}
+	}
+}
+
+func TestEncoder_WriteFrame_Errors(t *testing.T) {
+	sut, err := NewEncoder(&bytes.Buffer{}, syntheticHeader(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteFrame(&Frame{Command: CommandCustomData}, nil); err == nil {
+		t.Error("expected an error writing customdata to a protocol 3 demo, but received none")
+	}
+	if err := sut.WriteFrame(&Frame{Command: CommandPacket}, nil); err == nil {
+		t.Error("expected an error writing a packet without packet info, but received none")
+	}
+	if err := sut.WriteFrame(&Frame{Command: Command(10)}, nil); err == nil {
+		t.Error("expected an error writing an unknown command, but received none")
+	}
+}
+
+// limitedWriter accepts remaining bytes, then fails every write
+type limitedWriter struct {
+	remaining int
+}
+
+var errSinkFull = errors.New("sink full")
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.remaining {
+		w.remaining = 0
+		return 0, errSinkFull
+	}
+	w.remaining -= len(p)
+	return len(p), nil
+}
+
+func TestEncoder_WriteFrame_SinkError(t *testing.T) {
+	if _, err := NewEncoder(&limitedWriter{}, syntheticHeader(4)); !errors.Is(err, errSinkFull) {
+		t.Errorf("expected: %v, but received: %v", errSinkFull, err)
+	}
+
+	sut, err := NewEncoder(&limitedWriter{remaining: HeaderSize}, syntheticHeader(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame := &Frame{Command: CommandConsoleCmd, Tick: 1}
+	if err := sut.WriteFrame(frame, make([]byte, 64)); !errors.Is(err, errSinkFull) {
+		t.Errorf("expected: %v, but received: %v", errSinkFull, err)
+	}
+	if err := sut.WriteFrame(frame, nil); !errors.Is(err, errSinkFull) {
+		t.Errorf("expected: %v, but received: %v", errSinkFull, err)
+	}
+	if err := sut.Flush(); !errors.Is(err, errSinkFull) {
+		t.Errorf("expected: %v, but received: %v", errSinkFull, err)
+	}
+}
+
+func TestNewEncoder_LongHeaderString(t *testing.T) {
+	header := syntheticHeader(4)
+	header.MapName = string(make([]byte, MaxOSPath+1))
+	if _, err := NewEncoder(&bytes.Buffer{}, header); err != bitbuf.ErrStringTruncated {
+		t.Errorf("expected: %v, but received: %v", bitbuf.ErrStringTruncated, err)
+	}
+}
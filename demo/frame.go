@@ -0,0 +1,173 @@
+package demo
+
+import (
+	"fmt"
+
+	"github.com/galaco/bitbuf"
+)
+
+// Command identifies the type of a frame
+type Command uint8
+
+const (
+	// CommandSignon frames contain the network messages sent while connecting
+	CommandSignon Command = 1
+	// CommandPacket frames contain the network messages of a single tick
+	CommandPacket Command = 2
+	// CommandSyncTick frames mark the point at which the client clock is synced to the demo
+	CommandSyncTick Command = 3
+	// CommandConsoleCmd frames contain a console command
+	CommandConsoleCmd Command = 4
+	// CommandUserCmd frames contain a player's usercmd
+	CommandUserCmd Command = 5
+	// CommandDataTables frames contain the send tables and server classes
+	CommandDataTables Command = 6
+	// CommandStop marks the end of the demo
+	CommandStop Command = 7
+	// CommandCustomData frames contain data for a custom callback. Only present in protocol 4 demos.
+	CommandCustomData Command = 8
+	// CommandStringTables frames contain a snapshot of the string tables
+	CommandStringTables Command = 9
+)
+
+func (cmd Command) String() string {
+	switch cmd {
+	case CommandSignon:
+		return "dem_signon"
+	case CommandPacket:
+		return "dem_packet"
+	case CommandSyncTick:
+		return "dem_synctick"
+	case CommandConsoleCmd:
+		return "dem_consolecmd"
+	case CommandUserCmd:
+		return "dem_usercmd"
+	case CommandDataTables:
+		return "dem_datatables"
+	case CommandStop:
+		return "dem_stop"
+	case CommandCustomData:
+		return "dem_customdata"
+	case CommandStringTables:
+		return "dem_stringtables"
+	}
+	return fmt.Sprintf("Command(%d)", uint8(cmd))
+}
+
+// protocolWithCustomData is the first demo protocol to include dem_customdata
+// and a player slot in each frame
+const protocolWithCustomData = 4
+
+// decodeCommand returns the Command for a raw command byte.
+// Before protocol 4, dem_stringtables took the value dem_customdata now uses.
+func decodeCommand(raw uint8, demoProtocol int32) (Command, error) {
+	cmd := Command(raw)
+	if demoProtocol < protocolWithCustomData && cmd == CommandCustomData {
+		return CommandStringTables, nil
+	}
+	if cmd < CommandSignon || cmd > CommandStringTables || (demoProtocol < protocolWithCustomData && cmd == CommandStringTables) {
+		return 0, fmt.Errorf("demo unknown command %d", raw)
+	}
+	return cmd, nil
+}
+
+// encodeCommand returns the raw command byte for a Command. It is the inverse of decodeCommand.
+func encodeCommand(cmd Command, demoProtocol int32) (uint8, error) {
+	if demoProtocol < protocolWithCustomData {
+		switch cmd {
+		case CommandCustomData:
+			return 0, fmt.Errorf("demo %s requires protocol %d", cmd, protocolWithCustomData)
+		case CommandStringTables:
+			return uint8(CommandCustomData), nil
+		}
+	}
+	if cmd < CommandSignon || cmd > CommandStringTables {
+		return 0, fmt.Errorf("demo unknown command %d", uint8(cmd))
+	}
+	return uint8(cmd), nil
+}
+
+// CmdInfo is the view of a single player when a packet was recorded
+type CmdInfo struct {
+	Flags            int32
+	ViewOrigin       [3]float32
+	ViewAngles       [3]float32
+	LocalViewAngles  [3]float32
+	ViewOrigin2      [3]float32
+	ViewAngles2      [3]float32
+	LocalViewAngles2 [3]float32
+}
+
+// CmdInfoSize is the size of an encoded CmdInfo, in bytes
+const CmdInfoSize = 76
+
+// PacketInfo precedes the payload of signon and packet frames
+type PacketInfo struct {
+	// CmdInfo holds one entry per split screen player
+	CmdInfo     []CmdInfo
+	SequenceIn  int32
+	SequenceOut int32
+}
+
+// Frame is a single command from a demo
+type Frame struct {
+	Command Command
+	Tick    int32
+	// PlayerSlot is only present in protocol 4 demos
+	PlayerSlot uint8
+
+	// Packet is set for signon and packet frames
+	Packet *PacketInfo
+	// OutgoingSequence is set for usercmd frames
+	OutgoingSequence int32
+	// CustomDataType is set for customdata frames
+	CustomDataType int32
+
+	// Data contains the payload of the frame. It is empty for synctick and stop frames.
+	Data *bitbuf.Reader
+}
+
+// hasPayload returns whether frames of this type are followed by a length prefixed payload
+func (cmd Command) hasPayload() bool {
+	return cmd != CommandSyncTick && cmd != CommandStop
+}
+
+func readVector(buf *bitbuf.Reader) (vec [3]float32) {
+	for i := range vec {
+		vec[i], _ = buf.ReadFloat32()
+	}
+	return vec
+}
+
+func writeVector(writer *bitbuf.Writer, vec [3]float32) error {
+	for _, v := range vec {
+		if err := writer.WriteFloat32(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCmdInfo reads a CmdInfo from buf, which must be in sticky error mode.
+func readCmdInfo(buf *bitbuf.Reader) (info CmdInfo) {
+	info.Flags, _ = buf.ReadInt32()
+	info.ViewOrigin = readVector(buf)
+	info.ViewAngles = readVector(buf)
+	info.LocalViewAngles = readVector(buf)
+	info.ViewOrigin2 = readVector(buf)
+	info.ViewAngles2 = readVector(buf)
+	info.LocalViewAngles2 = readVector(buf)
+	return info
+}
+
+func writeCmdInfo(writer *bitbuf.Writer, info *CmdInfo) error {
+	if err := writer.WriteInt32(info.Flags); err != nil {
+		return err
+	}
+	for _, vec := range [][3]float32{info.ViewOrigin, info.ViewAngles, info.LocalViewAngles, info.ViewOrigin2, info.ViewAngles2, info.LocalViewAngles2} {
+		if err := writeVector(writer, vec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
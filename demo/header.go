@@ -0,0 +1,93 @@
+// Package demo reads Source engine demo (.dem) files.
+//
+// A demo is a fixed size Header followed by a sequence of frames. Each frame has a
+// Command, the tick it was recorded at, and for most commands a length prefixed
+// payload that is handed to the caller as a bitbuf.Reader.
+package demo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/galaco/bitbuf"
+)
+
+const (
+	// Filestamp identifies a Source engine demo file
+	Filestamp = "HL2DEMO"
+	// HeaderSize is the size of a demo header, in bytes
+	HeaderSize = 1072
+	// MaxOSPath is the size of each path field in the header, in bytes
+	MaxOSPath = 260
+)
+
+// ErrInvalidFilestamp is returned when a file does not begin with the demo Filestamp
+var ErrInvalidFilestamp = errors.New("demo invalid filestamp")
+
+// Header is the fixed size header at the start of every demo
+type Header struct {
+	Filestamp       string
+	DemoProtocol    int32
+	NetworkProtocol int32
+	ServerName      string
+	ClientName      string
+	MapName         string
+	GameDirectory   string
+	PlaybackTime    float32
+	PlaybackTicks   int32
+	PlaybackFrames  int32
+	SignonLength    int32
+}
+
+// readHeader reads a demo header from buf, which must be in sticky error mode.
+func readHeader(buf *bitbuf.Reader) (*Header, error) {
+	header := &Header{}
+	header.Filestamp, _ = buf.ReadFixedString(8)
+	header.DemoProtocol, _ = buf.ReadInt32()
+	header.NetworkProtocol, _ = buf.ReadInt32()
+	header.ServerName, _ = buf.ReadFixedString(MaxOSPath)
+	header.ClientName, _ = buf.ReadFixedString(MaxOSPath)
+	header.MapName, _ = buf.ReadFixedString(MaxOSPath)
+	header.GameDirectory, _ = buf.ReadFixedString(MaxOSPath)
+	header.PlaybackTime, _ = buf.ReadFloat32()
+	header.PlaybackTicks, _ = buf.ReadInt32()
+	header.PlaybackFrames, _ = buf.ReadInt32()
+	header.SignonLength, _ = buf.ReadInt32()
+	if err := buf.Err(); err != nil {
+		return nil, fmt.Errorf("demo header: %w", err)
+	}
+
+	if header.Filestamp != Filestamp {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFilestamp, header.Filestamp)
+	}
+	return header, nil
+}
+
+// writeHeader writes a demo header to writer.
+// Strings too long for their field return bitbuf.ErrStringTruncated.
+func writeHeader(writer *bitbuf.Writer, header *Header) error {
+	if err := writer.WriteFixedString(Filestamp, 8); err != nil {
+		return err
+	}
+	if err := writer.WriteInt32(header.DemoProtocol); err != nil {
+		return err
+	}
+	if err := writer.WriteInt32(header.NetworkProtocol); err != nil {
+		return err
+	}
+	for _, path := range []string{header.ServerName, header.ClientName, header.MapName, header.GameDirectory} {
+		if err := writer.WriteFixedString(path, MaxOSPath); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteFloat32(header.PlaybackTime); err != nil {
+		return err
+	}
+	if err := writer.WriteInt32(header.PlaybackTicks); err != nil {
+		return err
+	}
+	if err := writer.WriteInt32(header.PlaybackFrames); err != nil {
+		return err
+	}
+	return writer.WriteInt32(header.SignonLength)
+}
@@ -0,0 +1,94 @@
+// Package entity maintains the state of networked entities, by applying the deltas
+// sent in svc_PacketEntities to instance baselines and earlier updates.
+package entity
+
+import (
+	"fmt"
+
+	"github.com/galaco/bitbuf"
+	"github.com/galaco/bitbuf/netmsg"
+	"github.com/galaco/bitbuf/sendtable"
+)
+
+const (
+	// MaxEdicts is the maximum number of networked entities
+	MaxEdicts = 1 << netmsg.MaxEdictBits
+	// SerialBits is the width of an entity's serial number
+	SerialBits = 10
+)
+
+// Entity is a single networked entity
+type Entity struct {
+	Index  int
+	Serial uint32
+	Class  *sendtable.ServerClass
+	// Props holds the value of each prop, by index into Class.Props.
+	// Values are of the types returned by sendtable.DecodeProp.
+	Props []interface{}
+}
+
+// Prop returns the value of the prop with the given name. See sendtable.ServerClass.PropIndex.
+func (entity *Entity) Prop(name string) (interface{}, bool) {
+	index, ok := entity.Class.PropIndex(name)
+	if !ok || entity.Props[index] == nil {
+		return nil, false
+	}
+	return entity.Props[index], true
+}
+
+// Handle returns the handle that refers to this entity in EHANDLE props
+func (entity *Entity) Handle() uint32 {
+	return uint32(entity.Index) | entity.Serial<<netmsg.MaxEdictBits
+}
+
+// DecodeProps reads a list of changed props, as sent in entity updates and instance baselines.
+// Their values are stored in props, which is indexed as Class.Props, and their indices appended to changed.
+func DecodeProps(buf *bitbuf.Reader, class *sendtable.ServerClass, props []interface{}, changed []int) ([]int, error) {
+	if len(props) != len(class.Props) {
+		return changed, fmt.Errorf("entity class %s: %d prop values for %d props", class.Name, len(props), len(class.Props))
+	}
+	index := -1
+	for {
+		more, err := buf.ReadOneBit()
+		if err != nil {
+			return changed, err
+		}
+		if !more {
+			return changed, nil
+		}
+		delta, err := buf.ReadUBitVar()
+		if err != nil {
+			return changed, err
+		}
+		index += int(delta) + 1
+		if index >= len(class.Props) {
+			return changed, fmt.Errorf("entity class %s: prop index %d out of range", class.Name, index)
+		}
+
+		if props[index], err = sendtable.DecodeProp(buf, class.Props[index].Prop); err != nil {
+			return changed, fmt.Errorf("entity class %s: prop %s: %w", class.Name, class.Props[index].Name(), err)
+		}
+		changed = append(changed, index)
+	}
+}
+
+// EncodeProps writes the props at indices, which must be ascending, as a list of changed props
+func EncodeProps(writer *bitbuf.Writer, class *sendtable.ServerClass, props []interface{}, indices []int) error {
+	last := -1
+	for _, index := range indices {
+		if index <= last || index >= len(class.Props) {
+			return fmt.Errorf("entity class %s: prop index %d out of order or range", class.Name, index)
+		}
+		if err := writer.WriteOneBit(true); err != nil {
+			return err
+		}
+		if err := writer.WriteUBitVar(uint32(index - last - 1)); err != nil {
+			return err
+		}
+		if err := sendtable.EncodeProp(writer, class.Props[index].Prop, props[index]); err != nil {
+			return err
+		}
+		last = index
+	}
+	return writer.WriteOneBit(false)
+}
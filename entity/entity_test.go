@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+	"github.com/galaco/bitbuf/sendtable"
+)
+
+// syntheticTables returns two classes, a player and a prop
+func syntheticTables(t *testing.T) *sendtable.DataTables {
+	tables := []*sendtable.Table{
+		{Name: "DT_BaseEntity", Props: []*sendtable.Prop{
+			{Type: sendtable.PropTypeVector, Name: "m_vecOrigin", Flags: sendtable.FlagCoord, Priority: sendtable.DefaultPriority},
+			{Type: sendtable.PropTypeInt, Name: "m_iTeamNum", Flags: sendtable.FlagUnsigned, NumBits: 6, Priority: sendtable.DefaultPriority},
+		}},
+		{Name: "DT_BasePlayer", Props: []*sendtable.Prop{
+			{Type: sendtable.PropTypeDataTable, Name: "baseclass", DataTableName: "DT_BaseEntity", Priority: sendtable.DefaultPriority},
+			{Type: sendtable.PropTypeInt, Name: "m_iHealth", Flags: sendtable.FlagVarInt, Priority: sendtable.DefaultPriority},
+			{Type: sendtable.PropTypeString, Name: "m_szLastPlaceName", Priority: sendtable.DefaultPriority},
+		}},
+	}
+	classes := []*sendtable.ServerClass{
+		{ID: 0, Name: "CBaseEntity", DataTableName: "DT_BaseEntity"},
+		{ID: 1, Name: "CBasePlayer", DataTableName: "DT_BasePlayer"},
+	}
+	dt, err := sendtable.NewDataTables(tables, classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dt
+}
+
+func TestDecodeProps(t *testing.T) {
+	class := syntheticTables(t).Class(1)
+	expected := []interface{}{nil, int32(3), int32(-20), nil}
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := EncodeProps(writer, class, expected, []int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make([]interface{}, len(class.Props))
+	reader := bitbuf.NewReader(writer.Data())
+	changed, err := DecodeProps(reader, class, actual, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %v, but received: %v", expected, actual)
+	}
+	if !reflect.DeepEqual(changed, []int{1, 2}) {
+		t.Errorf("expected: %v, but received: %v", []int{1, 2}, changed)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestDecodeProps_IndexOutOfRange(t *testing.T) {
+	class := syntheticTables(t).Class(0)
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	writer.WriteOneBit(true)
+	writer.WriteUBitVar(5)
+	if _, err := DecodeProps(bitbuf.NewReader(writer.Data()), class, make([]interface{}, len(class.Props)), nil); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestDecodeProps_WrongLength(t *testing.T) {
+	class := syntheticTables(t).Class(1)
+	if _, err := DecodeProps(bitbuf.NewReader([]byte{0}), class, make([]interface{}, len(class.Props)-1), nil); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestEncodeProps_OutOfOrder(t *testing.T) {
+	class := syntheticTables(t).Class(1)
+	props := []interface{}{nil, int32(3), int32(-20), nil}
+	if err := EncodeProps(bitbuf.NewGrowableWriter(0, 0), class, props, []int{2, 1}); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestEntity_Prop(t *testing.T) {
+	class := syntheticTables(t).Class(1)
+	index, _ := class.PropIndex("m_iHealth")
+	sut := &Entity{Index: 1, Class: class, Props: make([]interface{}, len(class.Props))}
+	sut.Props[index] = int32(100)
+
+	if value, ok := sut.Prop("m_iHealth"); !ok || value != int32(100) {
+		t.Errorf("expected: %d, but received: %v", 100, value)
+	}
+	if _, ok := sut.Prop("m_iTeamNum"); ok {
+		t.Error("expected unset prop not to be found")
+	}
+	if _, ok := sut.Prop("m_iMissing"); ok {
+		t.Error("expected missing prop not to be found")
+	}
+}
+
+func TestEntity_Handle(t *testing.T) {
+	sut := &Entity{Index: 5, Serial: 3}
+	if sut.Handle() != 5|3<<11 {
+		t.Errorf("expected: %d, but received: %d", 5|3<<11, sut.Handle())
+	}
+}
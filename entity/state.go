@@ -0,0 +1,392 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+
+	"github.com/galaco/bitbuf"
+	"github.com/galaco/bitbuf/netmsg"
+	"github.com/galaco/bitbuf/sendtable"
+)
+
+// ErrNoBaseline is returned when an entity is created with neither an entity nor an instance baseline
+var ErrNoBaseline = errors.New("no baseline for entity")
+
+// Handler is called with an entity that has been created, has left the PVS, or been deleted
+type Handler func(entity *Entity) error
+
+// UpdateHandler is called with an entity that has been updated, and the indices of the props that changed
+type UpdateHandler func(entity *Entity, changed []int) error
+
+// baseline is the state an entity is created from
+type baseline struct {
+	class *sendtable.ServerClass
+	props []interface{}
+}
+
+// State holds every entity in the PVS, as updated by svc_PacketEntities.
+// Each update is applied to the current state, so updates must be applied in the order they were sent,
+// as they are recorded in a demo.
+type State struct {
+	tables    *sendtable.DataTables
+	classBits uint
+	entities  [MaxEdicts]*Entity
+
+	// instanceBaselines are the packed props of each class, as sent in the instancebaseline string table
+	instanceBaselines map[uint16][]byte
+	decodedBaselines  map[uint16][]interface{}
+	// entityBaselines are the two slots of per entity baselines the server may create from an update
+	entityBaselines [2]map[int]baseline
+
+	onCreate Handler
+	onUpdate UpdateHandler
+	onLeave  Handler
+	onDelete Handler
+}
+
+// NewState returns an empty State, for the server classes in tables
+func NewState(tables *sendtable.DataTables) *State {
+	classBits := uint(1)
+	if len(tables.Classes) > 0 {
+		classBits = uint(bits.Len32(uint32(len(tables.Classes))))
+	}
+	return &State{
+		tables:            tables,
+		classBits:         classBits,
+		instanceBaselines: map[uint16][]byte{},
+		decodedBaselines:  map[uint16][]interface{}{},
+		entityBaselines:   [2]map[int]baseline{{}, {}},
+	}
+}
+
+// OnCreate sets the handler called when an entity enters the PVS
+func (state *State) OnCreate(handler Handler) {
+	state.onCreate = handler
+}
+
+// OnUpdate sets the handler called when the props of an entity change
+func (state *State) OnUpdate(handler UpdateHandler) {
+	state.onUpdate = handler
+}
+
+// OnLeave sets the handler called when an entity leaves the PVS, before it is removed.
+// The entity may enter the PVS again later, at which point it is created again.
+func (state *State) OnLeave(handler Handler) {
+	state.onLeave = handler
+}
+
+// OnDelete sets the handler called when an entity is deleted, before it is removed
+func (state *State) OnDelete(handler Handler) {
+	state.onDelete = handler
+}
+
+// Entity returns the entity at index, or nil if there is none
+func (state *State) Entity(index int) *Entity {
+	if index < 0 || index >= MaxEdicts {
+		return nil
+	}
+	return state.entities[index]
+}
+
+// Entities returns every entity, in order of index
+func (state *State) Entities() []*Entity {
+	var entities []*Entity
+	for _, entity := range state.entities {
+		if entity != nil {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// SetInstanceBaseline sets the packed props new entities of a class are created from.
+// These are the entries of the instancebaseline string table.
+func (state *State) SetInstanceBaseline(classID uint16, data []byte) {
+	state.instanceBaselines[classID] = data
+	delete(state.decodedBaselines, classID)
+}
+
+// SetInstanceBaselineEntry sets an instance baseline from an instancebaseline string table entry,
+// which is keyed by the decimal class ID.
+func (state *State) SetInstanceBaselineEntry(key string, data []byte) error {
+	classID, err := strconv.ParseUint(key, 10, 16)
+	if err != nil {
+		return fmt.Errorf("instance baseline %q: %w", key, err)
+	}
+	state.SetInstanceBaseline(uint16(classID), data)
+	return nil
+}
+
+// instanceBaseline returns the decoded instance baseline of class
+func (state *State) instanceBaseline(class *sendtable.ServerClass) ([]interface{}, error) {
+	if props, ok := state.decodedBaselines[class.ID]; ok {
+		return props, nil
+	}
+	data, ok := state.instanceBaselines[class.ID]
+	if !ok {
+		return nil, nil
+	}
+	props := make([]interface{}, len(class.Props))
+	if _, err := DecodeProps(bitbuf.NewReader(data), class, props, nil); err != nil {
+		return nil, fmt.Errorf("instance baseline: %w", err)
+	}
+	state.decodedBaselines[class.ID] = props
+	return props, nil
+}
+
+// changeKind is the type of change made to an entity by an update
+type changeKind int
+
+const (
+	changeLeave changeKind = iota
+	changeDelete
+	changeCreate
+	changeUpdate
+)
+
+// change is a single entry of svc_PacketEntities, parsed but not yet applied
+type change struct {
+	kind  changeKind
+	index int
+	// entity is the entity created, or the existing entity updated
+	entity *Entity
+	// props are the new props of an updated entity
+	props   []interface{}
+	changed []int
+}
+
+// Apply applies the entity updates of msg to the state, calling handlers as entities change.
+// The whole message is parsed before any change is made, so if it is malformed the state is left
+// unchanged. An error returned by a handler stops the remaining changes from being applied.
+func (state *State) Apply(msg *netmsg.PacketEntities) error {
+	slot := 0
+	if msg.Baseline {
+		slot = 1
+	}
+	changes, baselines, deletions, err := state.parse(msg, slot)
+	if err != nil {
+		return err
+	}
+
+	// A full update replaces every entity
+	if !msg.IsDelta {
+		for index, entity := range state.entities {
+			if entity != nil {
+				if err := state.remove(index, state.onDelete); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if msg.UpdateBaseline {
+		// The server created a new baseline from the one this update is relative to,
+		// which entities entering the PVS now replace
+		state.entityBaselines[1-slot] = make(map[int]baseline, len(state.entityBaselines[slot]))
+		for index, entityBaseline := range state.entityBaselines[slot] {
+			state.entityBaselines[1-slot][index] = entityBaseline
+		}
+		for index, entityBaseline := range baselines {
+			state.entityBaselines[1-slot][index] = entityBaseline
+		}
+	}
+
+	for _, c := range changes {
+		var err error
+		switch c.kind {
+		case changeLeave:
+			err = state.remove(c.index, state.onLeave)
+		case changeDelete:
+			err = state.remove(c.index, state.onDelete)
+		case changeCreate:
+			err = state.commitCreate(c)
+		case changeUpdate:
+			c.entity.Props = c.props
+			if state.onUpdate != nil {
+				err = state.onUpdate(c.entity, c.changed)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, index := range deletions {
+		if err := state.remove(index, state.onDelete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse reads every entry of msg without changing the state. It returns the changes to entities,
+// the entity baselines to add when the message updates them, and the entities deleted by a delta.
+func (state *State) parse(msg *netmsg.PacketEntities, slot int) (changes []change, baselines map[int]baseline, deletions []int, err error) {
+	buf := bitbuf.NewReader(msg.Data)
+	baselines = map[int]baseline{}
+
+	index := -1
+	for i := uint32(0); i < msg.UpdatedEntries; i++ {
+		delta, err := buf.ReadUBitVar()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		index += int(delta) + 1
+		if index >= MaxEdicts {
+			return nil, nil, nil, fmt.Errorf("entity index %d out of range", index)
+		}
+
+		leave, err := buf.ReadOneBit()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if leave {
+			remove, err := buf.ReadOneBit()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			c := change{kind: changeLeave, index: index}
+			if remove {
+				c.kind = changeDelete
+			}
+			changes = append(changes, c)
+			continue
+		}
+
+		enter, err := buf.ReadOneBit()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var c change
+		if enter {
+			c, err = state.create(buf, index, msg.IsDelta, slot)
+			if err == nil && msg.UpdateBaseline {
+				props := make([]interface{}, len(c.entity.Props))
+				copy(props, c.entity.Props)
+				baselines[index] = baseline{class: c.entity.Class, props: props}
+			}
+		} else {
+			// A full update deletes every entity first, so there are none to update
+			var existing *Entity
+			if msg.IsDelta {
+				existing = state.entities[index]
+			}
+			c, err = state.update(buf, index, existing)
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	// Deltas explicitly delete entities that aren't in the PVS
+	if msg.IsDelta {
+		for {
+			more, err := buf.ReadOneBit()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if !more {
+				break
+			}
+			index, err := buf.ReadUint32Bits(netmsg.MaxEdictBits)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			deletions = append(deletions, int(index))
+		}
+	}
+	return changes, baselines, deletions, nil
+}
+
+// create reads an entity entering the PVS
+func (state *State) create(buf *bitbuf.Reader, index int, isDelta bool, slot int) (change, error) {
+	classID, err := buf.ReadUint32Bits(state.classBits)
+	if err != nil {
+		return change{}, err
+	}
+	serial, err := buf.ReadUint32Bits(SerialBits)
+	if err != nil {
+		return change{}, err
+	}
+	class := state.tables.Class(uint16(classID))
+	if class == nil {
+		return change{}, fmt.Errorf("entity %d: unknown server class %d", index, classID)
+	}
+
+	// Entities are created from their own baseline if they have one, otherwise from their class's
+	var from []interface{}
+	if entityBaseline, ok := state.entityBaselines[slot][index]; isDelta && ok && entityBaseline.class == class {
+		from = entityBaseline.props
+	} else if from, err = state.instanceBaseline(class); err != nil {
+		return change{}, fmt.Errorf("entity %d: %w", index, err)
+	}
+	if from == nil {
+		return change{}, fmt.Errorf("%w %d of class %s", ErrNoBaseline, index, class.Name)
+	}
+
+	entity := &Entity{
+		Index:  index,
+		Serial: serial,
+		Class:  class,
+		Props:  make([]interface{}, len(class.Props)),
+	}
+	copy(entity.Props, from)
+	changed, err := DecodeProps(buf, class, entity.Props, nil)
+	if err != nil {
+		return change{}, fmt.Errorf("entity %d: %w", index, err)
+	}
+	return change{kind: changeCreate, index: index, entity: entity, changed: changed}, nil
+}
+
+// commitCreate adds an entity that entered the PVS
+func (state *State) commitCreate(c change) error {
+	entity := c.entity
+	// An entity re-entering the PVS is an update, otherwise any entity it replaces is deleted
+	if existing := state.entities[c.index]; existing != nil {
+		if existing.Class == entity.Class && existing.Serial == entity.Serial {
+			existing.Props = entity.Props
+			if state.onUpdate != nil {
+				return state.onUpdate(existing, c.changed)
+			}
+			return nil
+		}
+		if err := state.remove(c.index, state.onDelete); err != nil {
+			return err
+		}
+	}
+	state.entities[c.index] = entity
+	if state.onCreate != nil {
+		return state.onCreate(entity)
+	}
+	return nil
+}
+
+// update reads changes to an entity in the PVS, into a copy of its props
+func (state *State) update(buf *bitbuf.Reader, index int, entity *Entity) (change, error) {
+	if entity == nil {
+		return change{}, fmt.Errorf("entity %d: update for an entity that doesn't exist", index)
+	}
+	props := make([]interface{}, len(entity.Props))
+	copy(props, entity.Props)
+	changed, err := DecodeProps(buf, entity.Class, props, nil)
+	if err != nil {
+		return change{}, fmt.Errorf("entity %d: %w", index, err)
+	}
+	return change{kind: changeUpdate, index: index, entity: entity, props: props, changed: changed}, nil
+}
+
+// remove removes the entity at index, if any, calling handler first
+func (state *State) remove(index int, handler Handler) error {
+	entity := state.entities[index]
+	if entity == nil {
+		return nil
+	}
+	state.entities[index] = nil
+	if handler != nil {
+		return handler(entity)
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+	"github.com/galaco/bitbuf/netmsg"
+)
+
+// packetWriter writes the entity updates of a PacketEntities message
+type packetWriter struct {
+	t       *testing.T
+	state   *State
+	writer  *bitbuf.Writer
+	last    int
+	updates uint32
+}
+
+func newPacketWriter(t *testing.T, state *State) *packetWriter {
+	return &packetWriter{t: t, state: state, writer: bitbuf.NewGrowableWriter(0, 0), last: -1}
+}
+
+func (packet *packetWriter) header(index int, leave bool, flag bool) {
+	packet.writer.WriteUBitVar(uint32(index - packet.last - 1))
+	packet.writer.WriteOneBit(leave)
+	packet.writer.WriteOneBit(flag)
+	packet.last = index
+	packet.updates++
+}
+
+func (packet *packetWriter) props(classID uint16, props map[int]interface{}) {
+	class := packet.state.tables.Class(classID)
+	values := make([]interface{}, len(class.Props))
+	var indices []int
+	for i := range class.Props {
+		if value, ok := props[i]; ok {
+			values[i] = value
+			indices = append(indices, i)
+		}
+	}
+	if err := EncodeProps(packet.writer, class, values, indices); err != nil {
+		packet.t.Fatal(err)
+	}
+}
+
+func (packet *packetWriter) enter(index int, classID uint16, serial uint32, props map[int]interface{}) {
+	packet.header(index, false, true)
+	packet.writer.WriteUnsignedBitInt32(uint32(classID), packet.state.classBits)
+	packet.writer.WriteUnsignedBitInt32(serial, SerialBits)
+	packet.props(classID, props)
+}
+
+func (packet *packetWriter) update(index int, classID uint16, props map[int]interface{}) {
+	packet.header(index, false, false)
+	packet.props(classID, props)
+}
+
+func (packet *packetWriter) message(isDelta bool, deletes ...int) *netmsg.PacketEntities {
+	if isDelta {
+		for _, index := range deletes {
+			packet.writer.WriteOneBit(true)
+			packet.writer.WriteUnsignedBitInt32(uint32(index), netmsg.MaxEdictBits)
+		}
+		packet.writer.WriteOneBit(false)
+	}
+	return &netmsg.PacketEntities{
+		MaxEntries:     MaxEdicts - 1,
+		IsDelta:        isDelta,
+		UpdatedEntries: packet.updates,
+		Length:         uint32(packet.writer.BitsWritten()),
+		Data:           packet.writer.Data(),
+	}
+}
+
+// recorder records the handlers called by a State
+type recorder struct {
+	events []string
+}
+
+func newRecordedState(t *testing.T) (*State, *recorder) {
+	sut := NewState(syntheticTables(t))
+	record := &recorder{}
+	sut.OnCreate(func(entity *Entity) error {
+		record.events = append(record.events, "create "+entity.Class.Name)
+		return nil
+	})
+	sut.OnUpdate(func(entity *Entity, changed []int) error {
+		record.events = append(record.events, "update "+entity.Class.Name)
+		return nil
+	})
+	sut.OnLeave(func(entity *Entity) error {
+		record.events = append(record.events, "leave "+entity.Class.Name)
+		return nil
+	})
+	sut.OnDelete(func(entity *Entity) error {
+		record.events = append(record.events, "delete "+entity.Class.Name)
+		return nil
+	})
+
+	baseline := bitbuf.NewGrowableWriter(0, 0)
+	if err := EncodeProps(baseline, sut.tables.Class(1), []interface{}{[3]float32{}, int32(2), int32(100), "spawn"}, []int{0, 1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.SetInstanceBaselineEntry("1", baseline.Data()); err != nil {
+		t.Fatal(err)
+	}
+	sut.SetInstanceBaseline(0, []byte{0})
+	return sut, record
+}
+
+func TestState_Apply(t *testing.T) {
+	sut, record := newRecordedState(t)
+
+	packet := newPacketWriter(t, sut)
+	packet.enter(1, 1, 7, map[int]interface{}{0: [3]float32{16, 32, 64}})
+	packet.enter(40, 0, 2, nil)
+	if err := sut.Apply(packet.message(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	player := sut.Entity(1)
+	expected := []interface{}{[3]float32{16, 32, 64}, int32(2), int32(100), "spawn"}
+	if player == nil || !reflect.DeepEqual(player.Props, expected) {
+		t.Fatalf("expected: %v, but received: %+v", expected, player)
+	}
+	if player.Serial != 7 {
+		t.Errorf("expected: %d, but received: %d", 7, player.Serial)
+	}
+
+	packet = newPacketWriter(t, sut)
+	packet.update(1, 1, map[int]interface{}{2: int32(35)})
+	packet.header(40, true, false)
+	if err := sut.Apply(packet.message(true)); err != nil {
+		t.Fatal(err)
+	}
+	if health, _ := player.Prop("m_iHealth"); health != int32(35) {
+		t.Errorf("expected: %d, but received: %v", 35, health)
+	}
+	if sut.Entity(40) != nil {
+		t.Error("expected entity that left the PVS to be removed")
+	}
+
+	packet = newPacketWriter(t, sut)
+	packet.enter(3, 0, 1, nil)
+	if err := sut.Apply(packet.message(true, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(sut.Entities()) != 1 || sut.Entity(3) == nil {
+		t.Errorf("expected: only entity 3, but received: %+v", sut.Entities())
+	}
+
+	// A full update replaces every entity
+	packet = newPacketWriter(t, sut)
+	packet.enter(2, 1, 1, nil)
+	if err := sut.Apply(packet.message(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEvents := []string{
+		"create CBasePlayer", "create CBaseEntity",
+		"update CBasePlayer", "leave CBaseEntity",
+		"create CBaseEntity", "delete CBasePlayer",
+		"delete CBaseEntity", "create CBasePlayer",
+	}
+	if !reflect.DeepEqual(record.events, expectedEvents) {
+		t.Errorf("expected: %v, but received: %v", expectedEvents, record.events)
+	}
+}
+
+func TestState_Apply_DeleteInHeader(t *testing.T) {
+	sut, record := newRecordedState(t)
+	packet := newPacketWriter(t, sut)
+	packet.enter(5, 1, 1, nil)
+	if err := sut.Apply(packet.message(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	packet = newPacketWriter(t, sut)
+	packet.header(5, true, true)
+	if err := sut.Apply(packet.message(true)); err != nil {
+		t.Fatal(err)
+	}
+	if sut.Entity(5) != nil {
+		t.Error("expected deleted entity to be removed")
+	}
+	if !reflect.DeepEqual(record.events, []string{"create CBasePlayer", "delete CBasePlayer"}) {
+		t.Errorf("expected: create then delete, but received: %v", record.events)
+	}
+}
+
+func TestState_Apply_ReplacesEntity(t *testing.T) {
+	sut, record := newRecordedState(t)
+	packet := newPacketWriter(t, sut)
+	packet.enter(5, 1, 1, nil)
+	if err := sut.Apply(packet.message(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-entering with the same serial updates the entity, a new serial replaces it
+	for _, serial := range []uint32{1, 2} {
+		packet = newPacketWriter(t, sut)
+		packet.enter(5, 1, serial, map[int]interface{}{2: int32(1)})
+		if err := sut.Apply(packet.message(true)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := []string{"create CBasePlayer", "update CBasePlayer", "delete CBasePlayer", "create CBasePlayer"}
+	if !reflect.DeepEqual(record.events, expected) {
+		t.Errorf("expected: %v, but received: %v", expected, record.events)
+	}
+}
+
+func TestState_Apply_EntityBaselines(t *testing.T) {
+	sut, _ := newRecordedState(t)
+
+	// The first update creates entity baselines in slot 1, from which later creations start
+	packet := newPacketWriter(t, sut)
+	packet.enter(1, 1, 1, map[int]interface{}{2: int32(50)})
+	msg := packet.message(false)
+	msg.UpdateBaseline = true
+	if err := sut.Apply(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	packet = newPacketWriter(t, sut)
+	packet.header(1, true, true)
+	if err := sut.Apply(packet.message(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	for slot, expected := range map[bool]int32{false: 100, true: 50} {
+		packet = newPacketWriter(t, sut)
+		packet.enter(1, 1, 1, nil)
+		msg = packet.message(true)
+		msg.Baseline = slot
+		if err := sut.Apply(msg); err != nil {
+			t.Fatal(err)
+		}
+		if health, _ := sut.Entity(1).Prop("m_iHealth"); health != expected {
+			t.Errorf("baseline %v. expected: %d, but received: %v", slot, expected, health)
+		}
+
+		packet = newPacketWriter(t, sut)
+		packet.header(1, true, true)
+		if err := sut.Apply(packet.message(true)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestState_Apply_Errors(t *testing.T) {
+	sut := NewState(syntheticTables(t))
+
+	packet := newPacketWriter(t, sut)
+	packet.enter(1, 1, 1, nil)
+	if err := sut.Apply(packet.message(false)); !errors.Is(err, ErrNoBaseline) {
+		t.Errorf("expected: %v, but received: %v", ErrNoBaseline, err)
+	}
+
+	packet = newPacketWriter(t, sut)
+	packet.update(1, 1, nil)
+	if err := sut.Apply(packet.message(true)); err == nil {
+		t.Error("expected an error updating an entity that doesn't exist, but received none")
+	}
+
+	packet = newPacketWriter(t, sut)
+	packet.header(1, false, true)
+	packet.writer.WriteUnsignedBitInt32(3, sut.classBits)
+	if err := sut.Apply(packet.message(false)); err == nil {
+		t.Error("expected an error for an unknown class, but received none")
+	}
+
+	if err := sut.SetInstanceBaselineEntry("player", nil); err == nil {
+		t.Error("expected an error for a non numeric key, but received none")
+	}
+}
+
+func TestState_Apply_Malformed(t *testing.T) {
+	sut, record := newRecordedState(t)
+	packet := newPacketWriter(t, sut)
+	packet.enter(1, 1, 1, map[int]interface{}{2: int32(50)})
+	msg := packet.message(false)
+	msg.UpdateBaseline = true
+	if err := sut.Apply(msg); err != nil {
+		t.Fatal(err)
+	}
+	record.events = nil
+
+	// Valid entries followed by one of an unknown class must leave the state as it was
+	for _, isDelta := range []bool{true, false} {
+		packet = newPacketWriter(t, sut)
+		packet.update(1, 1, map[int]interface{}{2: int32(10)})
+		packet.enter(2, 1, 1, nil)
+		packet.header(3, false, true)
+		packet.writer.WriteUnsignedBitInt32(3, sut.classBits)
+		msg = packet.message(isDelta)
+		msg.UpdateBaseline = true
+		if err := sut.Apply(msg); err == nil {
+			t.Fatal("expected an error for an unknown class, but received none")
+		}
+
+		if len(sut.Entities()) != 1 {
+			t.Errorf("expected: %d entities, but received: %d", 1, len(sut.Entities()))
+		}
+		if health, _ := sut.Entity(1).Prop("m_iHealth"); health != int32(50) {
+			t.Errorf("expected: %d, but received: %v", 50, health)
+		}
+		if len(sut.entityBaselines[0]) != 0 || len(sut.entityBaselines[1]) != 1 {
+			t.Errorf("expected entity baselines to be unchanged, but received: %v", sut.entityBaselines)
+		}
+		if len(record.events) != 0 {
+			t.Errorf("expected no handlers to be called, but received: %v", record.events)
+		}
+	}
+}
+
+func TestState_Apply_HandlerError(t *testing.T) {
+	sut, _ := newRecordedState(t)
+	handlerErr := errors.New("handler failed")
+	sut.OnCreate(func(entity *Entity) error {
+		return handlerErr
+	})
+	packet := newPacketWriter(t, sut)
+	packet.enter(1, 1, 1, nil)
+	if err := sut.Apply(packet.message(false)); err != handlerErr {
+		t.Errorf("expected: %v, but received: %v", handlerErr, err)
+	}
+}
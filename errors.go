@@ -0,0 +1,100 @@
+package bitbuf
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrOutOfBounds is matched by every *BoundsError when using errors.Is
+	ErrOutOfBounds = errors.New("bitbuf out of bounds")
+	// ErrWidthTooLarge is returned when more bits are requested in a single operation than it can handle
+	ErrWidthTooLarge = errors.New("bitbuf width too large")
+	// ErrMalformedVarInt is returned when a varint continues beyond its maximum length
+	ErrMalformedVarInt = errors.New("bitbuf malformed varint")
+	// ErrStringTruncated is returned when a string does not fit within its maximum length
+	ErrStringTruncated = errors.New("bitbuf string truncated")
+)
+
+// BoundsError is returned when a read, write or seek would go beyond the end of the buffer.
+type BoundsError struct {
+	// Op is the operation that failed: "read", "write" or "seek"
+	Op string
+	// BitPos is the position of the cursor when the operation failed
+	BitPos uint
+	// Requested is the number of bits the operation required
+	Requested uint
+	// Available is the number of bits that were available from BitPos
+	Available uint
+	// Err is the underlying cause, if any. Stream readers set this to
+	// io.EOF or io.ErrUnexpectedEOF when their source runs dry.
+	Err error
+}
+
+func (err *BoundsError) Error() string {
+	msg := fmt.Sprintf("bitbuf attempt oob %s by %d bits", err.Op, err.Requested-err.Available)
+	if err.Err != nil {
+		msg += ": " + err.Err.Error()
+	}
+	return msg
+}
+
+// Is allows BoundsError to be matched against ErrOutOfBounds
+func (err *BoundsError) Is(target error) bool {
+	return target == ErrOutOfBounds
+}
+
+// Unwrap returns the underlying cause of the error
+func (err *BoundsError) Unwrap() error {
+	return err.Err
+}
+
+// FlushedSeekError is returned when a stream Writer is asked to seek to a
+// position that has already been flushed to its sink.
+type FlushedSeekError struct {
+	// Position is the requested position, in bits
+	Position uint
+	// Flushed is the number of bits already flushed
+	Flushed uint
+}
+
+func (err *FlushedSeekError) Error() string {
+	return fmt.Sprintf("bitbuf attempt seek to bit %d behind flushed data ending at bit %d", err.Position, err.Flushed)
+}
+
+// StreamWindowError is returned when a stream Reader is asked to seek or read
+// behind its window. Data is discarded from the window once the cursor has passed it.
+type StreamWindowError struct {
+	// Op is the operation that failed: "read" or "seek"
+	Op string
+	// Position is the requested position, in bits
+	Position uint
+	// WindowStart is the first bit still held in the window
+	WindowStart uint
+}
+
+func (err *StreamWindowError) Error() string {
+	return fmt.Sprintf("bitbuf attempt %s at bit %d behind stream window starting at bit %d", err.Op, err.Position, err.WindowStart)
+}
+
+// exceeds returns whether numBits from bitPos run beyond totalBits, without
+// overflowing for huge numBits.
+func exceeds(bitPos uint, numBits uint, totalBits uint) bool {
+	return bitPos > totalBits || numBits > totalBits-bitPos
+}
+
+// newBoundsError returns a BoundsError for an operation requiring
+// numBits from bitPos, where only totalBits exist.
+func newBoundsError(op string, bitPos uint, numBits uint, totalBits uint, cause error) *BoundsError {
+	available := uint(0)
+	if totalBits > bitPos {
+		available = totalBits - bitPos
+	}
+	return &BoundsError{
+		Op:        op,
+		BitPos:    bitPos,
+		Requested: numBits,
+		Available: available,
+		Err:       cause,
+	}
+}
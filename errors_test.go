@@ -0,0 +1,58 @@
+package bitbuf
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBoundsError_Is(t *testing.T) {
+	err := error(&BoundsError{Op: "read", Requested: 8, Available: 0, Err: io.EOF})
+
+	if !errors.Is(err, ErrOutOfBounds) {
+		t.Error("expected BoundsError to match ErrOutOfBounds")
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Error("expected BoundsError to unwrap to its cause")
+	}
+	if errors.Is(err, ErrWidthTooLarge) {
+		t.Error("expected BoundsError not to match ErrWidthTooLarge")
+	}
+}
+
+func TestBoundsError_Error(t *testing.T) {
+	err := &BoundsError{Op: "write", BitPos: 4, Requested: 16, Available: 12}
+
+	expected := "bitbuf attempt oob write by 4 bits"
+	if err.Error() != expected {
+		t.Errorf("expected: %s, but received: %s", expected, err.Error())
+	}
+}
+
+func TestErrWidthTooLarge(t *testing.T) {
+	reader := NewReader(make([]byte, 16))
+	if _, err := reader.ReadUint64Bits(65); !errors.Is(err, ErrWidthTooLarge) {
+		t.Errorf("expected: %v, but received: %v", ErrWidthTooLarge, err)
+	}
+	if _, err := reader.ReadUint32Bits(33); !errors.Is(err, ErrWidthTooLarge) {
+		t.Errorf("expected: %v, but received: %v", ErrWidthTooLarge, err)
+	}
+
+	writer := NewWriter(16)
+	if err := writer.WriteUnsignedBitInt32(0, 33); !errors.Is(err, ErrWidthTooLarge) {
+		t.Errorf("expected: %v, but received: %v", ErrWidthTooLarge, err)
+	}
+}
+
+func TestWriter_BoundsError(t *testing.T) {
+	writer := NewGrowableWriter(0, 1)
+
+	err := writer.WriteUint16(1)
+	var boundsErr *BoundsError
+	if !errors.As(err, &boundsErr) {
+		t.Fatalf("expected: %T, but received: %v", boundsErr, err)
+	}
+	if boundsErr.Op != "write" || boundsErr.Requested != 16 || boundsErr.Available != 8 {
+		t.Errorf("unexpected bounds error: %+v", boundsErr)
+	}
+}
@@ -0,0 +1,61 @@
+// Package messages contains example message types, with bit encoders generated by bitbufgen.
+package messages
+
+//go:generate go run github.com/galaco/bitbuf/cmd/bitbufgen -type ServerInfo,PlayerUpdate -output messages_bitbuf.go
+
+// ServerInfo describes a server, as sent to a client on connection
+type ServerInfo struct {
+	Protocol     uint16
+	ServerCount  int32
+	IsHLTV       bool
+	IsDedicated  bool
+	ClientCRC    uint32
+	MaxClasses   uint16 `bitbuf:"bits=12"`
+	PlayerSlot   uint8
+	MaxClients   uint8
+	TickInterval float32
+	OS           uint8
+	GameDir      string `bitbuf:"max=260"`
+	MapName      string `bitbuf:"max=260"`
+	HostName     string
+	Classes      []ClassInfo `bitbuf:"len=12"`
+}
+
+// ClassInfo maps a server class to its data table
+type ClassInfo struct {
+	ClassID       uint16 `bitbuf:"bits=12"`
+	ClassName     string `bitbuf:"max=64"`
+	DataTableName string `bitbuf:"max=64"`
+}
+
+// PlayerUpdate is a compact update of a player's state
+type PlayerUpdate struct {
+	Entity   uint16     `bitbuf:"bits=11"`
+	Origin   [3]float32 `bitbuf:"vec3coord"`
+	Angles   [3]float32 `bitbuf:"angle=16"`
+	Forward  float32    `bitbuf:"normal"`
+	Height   float32    `bitbuf:"coord"`
+	Health   int8       `bitbuf:"signed,bits=7"`
+	Armor    uint32     `bitbuf:"varint"`
+	Velocity int32      `bitbuf:"signed,varint"`
+	Flags    uint64     `bitbuf:"bits=40"`
+	Ticks    int64      `bitbuf:"signed,bits=48"`
+	Score    int
+	Kills    uint
+	Deaths   int      `bitbuf:"signed"`
+	Weapons  [2]uint8 `bitbuf:"bits=5"`
+	Team     Team     `bitbuf:"bits=2"`
+	Name     Nickname `bitbuf:"max=32"`
+	Ammo     Ammo     `bitbuf:"len=3,bits=9"`
+	Secret   string   `bitbuf:"-"`
+	internal int
+}
+
+// Team is the team a player is on
+type Team uint8
+
+// Nickname is a player's display name
+type Nickname string
+
+// Ammo holds the ammunition of each of a player's weapons
+type Ammo []uint16
@@ -0,0 +1,439 @@
+// Code generated by bitbufgen; DO NOT EDIT.
+
+package messages
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/galaco/bitbuf"
+)
+
+// DecodeBits reads ServerInfo from buf
+func (v *ServerInfo) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(16)
+		if err != nil {
+			return err
+		}
+		v.Protocol = uint16(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.ServerCount = int32(x)
+	}
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.IsHLTV = x
+	}
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.IsDedicated = x
+	}
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.ClientCRC = x
+	}
+	{
+		x, err := buf.ReadUint32Bits(12)
+		if err != nil {
+			return err
+		}
+		v.MaxClasses = uint16(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		v.PlayerSlot = uint8(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		v.MaxClients = uint8(x)
+	}
+	{
+		x, err := buf.ReadFloat32()
+		if err != nil {
+			return err
+		}
+		v.TickInterval = x
+	}
+	{
+		x, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		v.OS = uint8(x)
+	}
+	{
+		x, err := buf.ReadString(260)
+		if err != nil {
+			return err
+		}
+		v.GameDir = x
+	}
+	{
+		x, err := buf.ReadString(260)
+		if err != nil {
+			return err
+		}
+		v.MapName = x
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.HostName = x
+	}
+	{
+		n, err := buf.ReadUint32Bits(12)
+		if err != nil {
+			return err
+		}
+		if err := buf.CheckAvailable(uint(n)); err != nil {
+			return err
+		}
+		v.Classes = make([]ClassInfo, n)
+	}
+	for i0 := range v.Classes {
+		if err := v.Classes[i0].DecodeBits(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeBits writes ServerInfo to buf
+func (v *ServerInfo) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.Protocol), 16); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.ServerCount), 32); err != nil {
+		return err
+	}
+	if err := buf.WriteOneBit(v.IsHLTV); err != nil {
+		return err
+	}
+	if err := buf.WriteOneBit(v.IsDedicated); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.ClientCRC), 32); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.MaxClasses), 12); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.PlayerSlot), 8); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.MaxClients), 8); err != nil {
+		return err
+	}
+	if err := buf.WriteFloat32(v.TickInterval); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.OS), 8); err != nil {
+		return err
+	}
+	if len(v.GameDir) >= 260 {
+		return bitbuf.ErrStringTruncated
+	}
+	if err := buf.WriteString(v.GameDir + "\x00"); err != nil {
+		return err
+	}
+	if len(v.MapName) >= 260 {
+		return bitbuf.ErrStringTruncated
+	}
+	if err := buf.WriteString(v.MapName + "\x00"); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.HostName + "\x00"); err != nil {
+		return err
+	}
+	if uint64(len(v.Classes)) >= 1<<12 {
+		return fmt.Errorf("bitbuf: %d elements do not fit in a 12 bit length", len(v.Classes))
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(len(v.Classes)), 12); err != nil {
+		return err
+	}
+	for i0 := range v.Classes {
+		if err := v.Classes[i0].EncodeBits(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBits reads PlayerUpdate from buf
+func (v *PlayerUpdate) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(11)
+		if err != nil {
+			return err
+		}
+		v.Entity = uint16(x)
+	}
+	{
+		x, err := buf.ReadBitVec3Coord()
+		if err != nil {
+			return err
+		}
+		v.Origin = x
+	}
+	for i0 := range v.Angles {
+		{
+			x, err := buf.ReadBitAngle(16)
+			if err != nil {
+				return err
+			}
+			v.Angles[i0] = x
+		}
+	}
+	{
+		x, err := buf.ReadBitNormal()
+		if err != nil {
+			return err
+		}
+		v.Forward = x
+	}
+	{
+		x, err := buf.ReadBitCoord()
+		if err != nil {
+			return err
+		}
+		v.Height = x
+	}
+	{
+		x, err := buf.ReadInt32Bits(7)
+		if err != nil {
+			return err
+		}
+		v.Health = int8(x)
+	}
+	{
+		x, err := buf.ReadVarInt32()
+		if err != nil {
+			return err
+		}
+		v.Armor = x
+	}
+	{
+		x, err := buf.ReadSignedVarInt32()
+		if err != nil {
+			return err
+		}
+		v.Velocity = x
+	}
+	{
+		x, err := buf.ReadUint64Bits(40)
+		if err != nil {
+			return err
+		}
+		v.Flags = x
+	}
+	{
+		x, err := buf.ReadInt64Bits(48)
+		if err != nil {
+			return err
+		}
+		v.Ticks = x
+	}
+	{
+		x, err := buf.ReadUint64Bits(bits.UintSize)
+		if err != nil {
+			return err
+		}
+		v.Score = int(x)
+	}
+	{
+		x, err := buf.ReadUint64Bits(bits.UintSize)
+		if err != nil {
+			return err
+		}
+		v.Kills = uint(x)
+	}
+	{
+		x, err := buf.ReadInt64Bits(bits.UintSize)
+		if err != nil {
+			return err
+		}
+		v.Deaths = int(x)
+	}
+	for i0 := range v.Weapons {
+		{
+			x, err := buf.ReadUint32Bits(5)
+			if err != nil {
+				return err
+			}
+			v.Weapons[i0] = uint8(x)
+		}
+	}
+	{
+		x, err := buf.ReadUint32Bits(2)
+		if err != nil {
+			return err
+		}
+		v.Team = Team(x)
+	}
+	{
+		x, err := buf.ReadString(32)
+		if err != nil {
+			return err
+		}
+		v.Name = Nickname(x)
+	}
+	{
+		n, err := buf.ReadUint32Bits(3)
+		if err != nil {
+			return err
+		}
+		if err := buf.CheckAvailable(uint(n)); err != nil {
+			return err
+		}
+		v.Ammo = make([]uint16, n)
+	}
+	for i0 := range v.Ammo {
+		{
+			x, err := buf.ReadUint32Bits(9)
+			if err != nil {
+				return err
+			}
+			v.Ammo[i0] = uint16(x)
+		}
+	}
+	return nil
+}
+
+// EncodeBits writes PlayerUpdate to buf
+func (v *PlayerUpdate) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.Entity), 11); err != nil {
+		return err
+	}
+	if err := buf.WriteBitVec3Coord(v.Origin); err != nil {
+		return err
+	}
+	for i0 := range v.Angles {
+		if err := buf.WriteBitAngle(float32(v.Angles[i0]), 16); err != nil {
+			return err
+		}
+	}
+	if err := buf.WriteBitNormal(float32(v.Forward)); err != nil {
+		return err
+	}
+	if err := buf.WriteBitCoord(float32(v.Height)); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.Health), 7); err != nil {
+		return err
+	}
+	if err := buf.WriteVarInt32(uint32(v.Armor)); err != nil {
+		return err
+	}
+	if err := buf.WriteSignedVarInt32(int32(v.Velocity)); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt64(uint64(v.Flags), 40); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt64(uint64(v.Ticks), 48); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt64(uint64(v.Score), bits.UintSize); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt64(uint64(v.Kills), bits.UintSize); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt64(uint64(v.Deaths), bits.UintSize); err != nil {
+		return err
+	}
+	for i0 := range v.Weapons {
+		if err := buf.WriteUnsignedBitInt32(uint32(v.Weapons[i0]), 5); err != nil {
+			return err
+		}
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.Team), 2); err != nil {
+		return err
+	}
+	if len(string(v.Name)) >= 32 {
+		return bitbuf.ErrStringTruncated
+	}
+	if err := buf.WriteString(string(v.Name) + "\x00"); err != nil {
+		return err
+	}
+	if uint64(len(v.Ammo)) >= 1<<3 {
+		return fmt.Errorf("bitbuf: %d elements do not fit in a 3 bit length", len(v.Ammo))
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(len(v.Ammo)), 3); err != nil {
+		return err
+	}
+	for i0 := range v.Ammo {
+		if err := buf.WriteUnsignedBitInt32(uint32(v.Ammo[i0]), 9); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBits reads ClassInfo from buf
+func (v *ClassInfo) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(12)
+		if err != nil {
+			return err
+		}
+		v.ClassID = uint16(x)
+	}
+	{
+		x, err := buf.ReadString(64)
+		if err != nil {
+			return err
+		}
+		v.ClassName = x
+	}
+	{
+		x, err := buf.ReadString(64)
+		if err != nil {
+			return err
+		}
+		v.DataTableName = x
+	}
+	return nil
+}
+
+// EncodeBits writes ClassInfo to buf
+func (v *ClassInfo) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.ClassID), 12); err != nil {
+		return err
+	}
+	if len(v.ClassName) >= 64 {
+		return bitbuf.ErrStringTruncated
+	}
+	if err := buf.WriteString(v.ClassName + "\x00"); err != nil {
+		return err
+	}
+	if len(v.DataTableName) >= 64 {
+		return bitbuf.ErrStringTruncated
+	}
+	if err := buf.WriteString(v.DataTableName + "\x00"); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Code generated by bitbufgen; DO NOT EDIT.
+
+package messages
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+)
+
+func TestServerInfo_BitsRoundTrip(t *testing.T) {
+	expected := ServerInfo{Protocol: uint16(23130), ServerCount: int32(-1073741824), IsHLTV: true, IsDedicated: true, ClientCRC: uint32(1515870810), MaxClasses: uint16(1445), PlayerSlot: uint8(90), MaxClients: uint8(90), TickInterval: float32(2106.25), OS: uint8(90), GameDir: "bitbuf", MapName: "bitbuf", HostName: "bitbuf", Classes: []ClassInfo{ClassInfo{ClassID: uint16(1445), ClassName: "bitbuf", DataTableName: "bitbuf"}, ClassInfo{ClassID: uint16(1445), ClassName: "bitbuf", DataTableName: "bitbuf"}}}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual ServerInfo
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestPlayerUpdate_BitsRoundTrip(t *testing.T) {
+	expected := PlayerUpdate{Entity: uint16(722), Origin: [3]float32{1.5, 0, -2.25}, Angles: [3]float32{float32(90), float32(90), float32(90)}, Forward: float32(-1), Height: float32(-1024.5), Health: int8(-64), Armor: uint32(300), Velocity: int32(-300), Flags: uint64(388062927450), Ticks: int64(-140737488355328), Score: int(757935405), Kills: uint(1515870810), Deaths: int(-2147483648), Weapons: [2]uint8{uint8(11), uint8(11)}, Team: Team(1), Name: "bitbuf", Ammo: []uint16{uint16(180), uint16(180)}}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual PlayerUpdate
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestClassInfo_BitsRoundTrip(t *testing.T) {
+	expected := ClassInfo{ClassID: uint16(1445), ClassName: "bitbuf", DataTableName: "bitbuf"}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual ClassInfo
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
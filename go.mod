@@ -1 +1,3 @@
 module github.com/galaco/bitbuf
+
+go 1.17
@@ -0,0 +1,100 @@
+// Package tag parses the `bitbuf` struct tags shared by bitbuf.Marshal,
+// bitbuf.Unmarshal and the bitbufgen code generator.
+package tag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Name is the struct tag key read by Parse
+const Name = "bitbuf"
+
+// Options describes how a single struct field is encoded
+type Options struct {
+	// Skip excludes the field entirely (`bitbuf:"-"`)
+	Skip bool
+	// Bits overrides the number of bits used for an integer field (`bits=N`)
+	Bits uint
+	// Signed sign-extends integer fields, or zigzag encodes varints (`signed`)
+	Signed bool
+	// VarInt encodes an integer field as a base 128 varint (`varint`)
+	VarInt bool
+	// CString marks a string as null-terminated (`cstring`). This is the default for strings.
+	CString bool
+	// Max is the maximum length in bytes of a string field, including its terminator (`max=N`)
+	Max uint
+	// Coord encodes a float field with ReadBitCoord (`coord`)
+	Coord bool
+	// Normal encodes a float field with ReadBitNormal (`normal`)
+	Normal bool
+	// Angle encodes a float field with ReadBitAngle, using the given number of bits (`angle=N`)
+	Angle uint
+	// Vec3Coord encodes a [3]float32 field with ReadBitVec3Coord (`vec3coord`)
+	Vec3Coord bool
+	// LenBits is the number of bits used for the element count of a slice (`len=N`)
+	LenBits uint
+}
+
+// Parse parses the value of a `bitbuf` struct tag
+func Parse(tag string) (opts Options, err error) {
+	if tag == "" {
+		return opts, nil
+	}
+	if tag == "-" {
+		opts.Skip = true
+		return opts, nil
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		key, value := option, ""
+		if i := strings.IndexByte(option, '='); i >= 0 {
+			key, value = option[:i], option[i+1:]
+		}
+
+		switch key {
+		case "signed":
+			opts.Signed = true
+		case "varint":
+			opts.VarInt = true
+		case "cstring":
+			opts.CString = true
+		case "coord":
+			opts.Coord = true
+		case "normal":
+			opts.Normal = true
+		case "vec3coord":
+			opts.Vec3Coord = true
+		case "bits":
+			opts.Bits, err = parseWidth(key, value, 64)
+		case "angle":
+			opts.Angle, err = parseWidth(key, value, 32)
+		case "len":
+			opts.LenBits, err = parseWidth(key, value, 32)
+		case "max":
+			var max uint64
+			max, err = strconv.ParseUint(value, 10, 32)
+			opts.Max = uint(max)
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return opts, fmt.Errorf("invalid bitbuf tag %q: %w", tag, err)
+		}
+	}
+
+	return opts, nil
+}
+
+// parseWidth parses a bit width in the range [1..max]
+func parseWidth(key string, value string, max uint) (uint, error) {
+	width, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number: %w", key, err)
+	}
+	if width == 0 || uint(width) > max {
+		return 0, fmt.Errorf("%s must be between 1 and %d", key, max)
+	}
+	return uint(width), nil
+}
@@ -0,0 +1,46 @@
+package tag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		expected Options
+	}{
+		{"", Options{}},
+		{"-", Options{Skip: true}},
+		{"bits=11", Options{Bits: 11}},
+		{"signed,bits=7", Options{Signed: true, Bits: 7}},
+		{"varint", Options{VarInt: true}},
+		{"signed,varint", Options{Signed: true, VarInt: true}},
+		{"cstring,max=260", Options{CString: true, Max: 260}},
+		{"coord", Options{Coord: true}},
+		{"normal", Options{Normal: true}},
+		{"angle=16", Options{Angle: 16}},
+		{"vec3coord", Options{Vec3Coord: true}},
+		{"len=8,bits=4", Options{LenBits: 8, Bits: 4}},
+	}
+
+	for _, tc := range testCases {
+		opts, err := Parse(tc.tag)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(opts, tc.expected) {
+			t.Errorf("tag %q. expected: %+v, but received: %+v", tc.tag, tc.expected, opts)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tags := []string{"bits", "bits=0", "bits=65", "bits=x", "angle=33", "len=0", "max=-1", "unknown"}
+
+	for _, tag := range tags {
+		if _, err := Parse(tag); err == nil {
+			t.Errorf("expected tag %q to be invalid", tag)
+		}
+	}
+}
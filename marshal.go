@@ -0,0 +1,305 @@
+package bitbuf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/galaco/bitbuf/internal/tag"
+)
+
+// Unmarshal reads the exported fields of the struct pointed to by v from buf, in declaration order.
+//
+// By default every field is read at its full width, with floats read as raw IEEE 754 bits and
+// strings read until a null terminator. The encoding of a field can be changed with a `bitbuf` tag:
+//
+//	`bitbuf:"bits=11"`          read an integer of 11 bits
+//	`bitbuf:"signed,bits=7"`    read a sign-extended integer of 7 bits
+//	`bitbuf:"varint"`           read a varint; with signed, a zigzag encoded varint
+//	`bitbuf:"cstring,max=260"`  read a null-terminated string of at most 260 bytes, including the terminator
+//	`bitbuf:"coord"`            read a float with ReadBitCoord
+//	`bitbuf:"normal"`           read a float with ReadBitNormal
+//	`bitbuf:"angle=16"`         read a float with ReadBitAngle(16)
+//	`bitbuf:"vec3coord"`        read a [3]float32 with ReadBitVec3Coord
+//	`bitbuf:"len=8"`            read a slice, prefixed by an 8 bit element count
+//	`bitbuf:"-"`                skip the field
+//
+// Nested structs are read recursively. Options on an array or slice apply to each element.
+func Unmarshal(buf *Reader, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("bitbuf: Unmarshal requires a non-nil pointer to a struct")
+	}
+	return unmarshalStruct(buf, rv.Elem())
+}
+
+// Marshal writes the exported fields of the struct v to writer, in declaration order.
+// v may be a struct or a pointer to one. Fields are encoded as described by Unmarshal.
+func Marshal(writer *Writer, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return errors.New("bitbuf: Marshal requires a struct or a non-nil pointer to a struct")
+	}
+	return marshalStruct(writer, rv)
+}
+
+func unmarshalStruct(buf *Reader, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		opts, err := tag.Parse(field.Tag.Get(tag.Name))
+		if err != nil {
+			return fmt.Errorf("bitbuf: field %s: %w", field.Name, err)
+		}
+		if opts.Skip {
+			continue
+		}
+		if err := unmarshalValue(buf, rv.Field(i), opts); err != nil {
+			return fmt.Errorf("bitbuf: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func unmarshalValue(buf *Reader, rv reflect.Value, opts tag.Options) error {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return unmarshalStruct(buf, rv)
+	case reflect.Array:
+		if opts.Vec3Coord {
+			if rv.Type() != reflect.TypeOf([3]float32{}) {
+				return fmt.Errorf("vec3coord requires [3]float32, not %s", rv.Type())
+			}
+			vec, err := buf.ReadBitVec3Coord()
+			if err != nil {
+				return err
+			}
+			rv.Set(reflect.ValueOf(vec))
+			return nil
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := unmarshalValue(buf, rv.Index(i), opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if opts.LenBits == 0 {
+			return errors.New("slices require a len option")
+		}
+		length, err := buf.ReadUint32Bits(opts.LenBits)
+		if err != nil {
+			return err
+		}
+		// Every element takes at least 1 bit, so a length that can't fit is rejected before allocating
+		if err := buf.CheckAvailable(uint(length)); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(rv.Type(), int(length), int(length))
+		for i := 0; i < slice.Len(); i++ {
+			if err := unmarshalValue(buf, slice.Index(i), opts); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+		return nil
+	case reflect.Bool:
+		v, err := buf.ReadOneBit()
+		rv.SetBool(v)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := unmarshalInteger(buf, rv.Type(), opts)
+		rv.SetInt(int64(v))
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v, err := unmarshalInteger(buf, rv.Type(), opts)
+		rv.SetUint(v)
+		return err
+	case reflect.Float32, reflect.Float64:
+		v, err := unmarshalFloat(buf, rv.Type(), opts)
+		rv.SetFloat(v)
+		return err
+	case reflect.String:
+		v, err := buf.ReadString(opts.Max)
+		rv.SetString(v)
+		return err
+	}
+	return fmt.Errorf("unsupported type %s", rv.Type())
+}
+
+// unmarshalInteger reads an integer of any kind, returning its bits as a uint64
+func unmarshalInteger(buf *Reader, rt reflect.Type, opts tag.Options) (uint64, error) {
+	size := uint(rt.Bits())
+	if opts.VarInt {
+		switch {
+		case size > 32 && opts.Signed:
+			v, err := buf.ReadSignedVarInt64()
+			return uint64(v), err
+		case size > 32:
+			return buf.ReadVarInt64()
+		case opts.Signed:
+			v, err := buf.ReadSignedVarInt32()
+			return uint64(v), err
+		default:
+			v, err := buf.ReadVarInt32()
+			return uint64(v), err
+		}
+	}
+
+	numBits := size
+	if opts.Bits != 0 {
+		numBits = opts.Bits
+	}
+	if numBits > size {
+		return 0, fmt.Errorf("%d bits do not fit in %s", numBits, rt)
+	}
+	if opts.Signed {
+		v, err := buf.ReadInt64Bits(numBits)
+		return uint64(v), err
+	}
+	return buf.ReadUint64Bits(numBits)
+}
+
+func unmarshalFloat(buf *Reader, rt reflect.Type, opts tag.Options) (float64, error) {
+	switch {
+	case opts.Coord:
+		v, err := buf.ReadBitCoord()
+		return float64(v), err
+	case opts.Normal:
+		v, err := buf.ReadBitNormal()
+		return float64(v), err
+	case opts.Angle != 0:
+		v, err := buf.ReadBitAngle(opts.Angle)
+		return float64(v), err
+	case rt.Kind() == reflect.Float32:
+		v, err := buf.ReadFloat32()
+		return float64(v), err
+	default:
+		return buf.ReadFloat64()
+	}
+}
+
+func marshalStruct(writer *Writer, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		opts, err := tag.Parse(field.Tag.Get(tag.Name))
+		if err != nil {
+			return fmt.Errorf("bitbuf: field %s: %w", field.Name, err)
+		}
+		if opts.Skip {
+			continue
+		}
+		if err := marshalValue(writer, rv.Field(i), opts); err != nil {
+			return fmt.Errorf("bitbuf: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func marshalValue(writer *Writer, rv reflect.Value, opts tag.Options) error {
+	switch rv.Kind() {
+	case reflect.Struct:
+		return marshalStruct(writer, rv)
+	case reflect.Array:
+		if opts.Vec3Coord {
+			vec, ok := rv.Interface().([3]float32)
+			if !ok {
+				return fmt.Errorf("vec3coord requires [3]float32, not %s", rv.Type())
+			}
+			return writer.WriteBitVec3Coord(vec)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := marshalValue(writer, rv.Index(i), opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if opts.LenBits == 0 {
+			return errors.New("slices require a len option")
+		}
+		if uint64(rv.Len()) >= uint64(1)<<opts.LenBits {
+			return fmt.Errorf("%d elements do not fit in a %d bit length", rv.Len(), opts.LenBits)
+		}
+		if err := writer.WriteUnsignedBitInt32(uint32(rv.Len()), opts.LenBits); err != nil {
+			return err
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := marshalValue(writer, rv.Index(i), opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Bool:
+		return writer.WriteOneBit(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return marshalInteger(writer, uint64(rv.Int()), rv.Type(), opts)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return marshalInteger(writer, rv.Uint(), rv.Type(), opts)
+	case reflect.Float32, reflect.Float64:
+		return marshalFloat(writer, rv.Float(), rv.Type(), opts)
+	case reflect.String:
+		return marshalString(writer, rv.String(), opts)
+	}
+	return fmt.Errorf("unsupported type %s", rv.Type())
+}
+
+// marshalInteger writes an integer of any kind, passed as its bits in a uint64
+func marshalInteger(writer *Writer, v uint64, rt reflect.Type, opts tag.Options) error {
+	size := uint(rt.Bits())
+	if opts.VarInt {
+		switch {
+		case size > 32 && opts.Signed:
+			return writer.WriteSignedVarInt64(int64(v))
+		case size > 32:
+			return writer.WriteVarInt64(v)
+		case opts.Signed:
+			return writer.WriteSignedVarInt32(int32(v))
+		default:
+			return writer.WriteVarInt32(uint32(v))
+		}
+	}
+
+	numBits := size
+	if opts.Bits != 0 {
+		numBits = opts.Bits
+	}
+	if numBits > size {
+		return fmt.Errorf("%d bits do not fit in %s", numBits, rt)
+	}
+	return writer.WriteUnsignedBitInt64(v, numBits)
+}
+
+func marshalFloat(writer *Writer, v float64, rt reflect.Type, opts tag.Options) error {
+	switch {
+	case opts.Coord:
+		return writer.WriteBitCoord(float32(v))
+	case opts.Normal:
+		return writer.WriteBitNormal(float32(v))
+	case opts.Angle != 0:
+		return writer.WriteBitAngle(float32(v), opts.Angle)
+	case rt.Kind() == reflect.Float32:
+		return writer.WriteFloat32(float32(v))
+	default:
+		return writer.WriteFloat64(v)
+	}
+}
+
+// marshalString writes a null-terminated string. If the string and its terminator
+// don't fit in max bytes, ErrStringTruncated is returned and nothing is written.
+func marshalString(writer *Writer, v string, opts tag.Options) error {
+	if opts.Max != 0 && uint(len(v)) >= opts.Max {
+		return ErrStringTruncated
+	}
+	return writer.WriteCString(v)
+}
@@ -0,0 +1,166 @@
+package bitbuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type marshalTestVector struct {
+	Origin [3]float32 `bitbuf:"vec3coord"`
+	Angle  float32    `bitbuf:"angle=16"`
+}
+
+type marshalTestMessage struct {
+	Type      uint8 `bitbuf:"bits=6"`
+	Reliable  bool
+	Delta     int32  `bitbuf:"signed,bits=7"`
+	Count     uint32 `bitbuf:"varint"`
+	Offset    int64  `bitbuf:"signed,varint"`
+	Name      string `bitbuf:"cstring,max=8"`
+	Map       string
+	X         float32 `bitbuf:"coord"`
+	Normal    float32 `bitbuf:"normal"`
+	Raw       float64
+	Flags     [3]uint16 `bitbuf:"bits=12"`
+	Vector    marshalTestVector
+	Entities  []uint16            `bitbuf:"len=5,bits=11"`
+	Children  []marshalTestVector `bitbuf:"len=2"`
+	Skipped   int                 `bitbuf:"-"`
+	unexposed int
+}
+
+func TestMarshal(t *testing.T) {
+	expected := marshalTestMessage{
+		Type:     42,
+		Reliable: true,
+		Delta:    -1,
+		Count:    300,
+		Offset:   -1234567890123,
+		Name:     "fits 7!",
+		Map:      "de_dust2",
+		X:        -1024.5,
+		Normal:   1,
+		Raw:      -756351.123,
+		Flags:    [3]uint16{1, 2047, 4095},
+		Vector:   marshalTestVector{Origin: [3]float32{1, 0, -2.25}, Angle: 90},
+		Entities: []uint16{1, 2, 2047},
+		Children: []marshalTestVector{{Angle: 180}, {Origin: [3]float32{0, 16, 0}}},
+	}
+
+	writer := NewGrowableWriter(0, 0)
+	if err := Marshal(writer, &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	var actual marshalTestMessage
+	reader := NewReader(writer.Data())
+	if err := Unmarshal(reader, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestMarshal_Truncation(t *testing.T) {
+	// Name has a max of 8 bytes, including its terminator
+	if err := Marshal(NewGrowableWriter(0, 0), marshalTestMessage{Name: "8 chars!"}); !errors.Is(err, ErrStringTruncated) {
+		t.Errorf("expected: %v, but received: %v", ErrStringTruncated, err)
+	}
+
+	writer := NewGrowableWriter(0, 0)
+	if err := Marshal(writer, marshalTestMessage{Name: "7 chars"}); err != nil {
+		t.Fatal(err)
+	}
+	var actual marshalTestMessage
+	if err := Unmarshal(NewReader(writer.Data()), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual.Name != "7 chars" {
+		t.Errorf("expected: %s, but received: %s", "7 chars", actual.Name)
+	}
+}
+
+func TestUnmarshal_SliceLengthTooLarge(t *testing.T) {
+	type message struct {
+		Values []uint8 `bitbuf:"len=32"`
+	}
+	// 0xffffffff elements, followed by only 1 byte of data
+	var actual message
+	err := Unmarshal(NewReader([]byte{0xff, 0xff, 0xff, 0xff, 1}), &actual)
+	if !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+}
+
+func TestUnmarshal_BinaryLayout(t *testing.T) {
+	type foo struct {
+		A byte
+		B int16
+		C float32
+		D int64
+		E [32]byte
+		F uint8
+		G float64
+		H int8
+		I uint32
+	}
+	expected := foo{
+		A: 32,
+		B: 8375,
+		C: 2106.3212345,
+		D: 5635455352,
+		E: [32]byte{84, 12, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 12, 13, 54, 1, 143, 234, 5, 56, 1, 2},
+		F: 213,
+		G: -756351.123,
+		H: -57,
+		I: 12645123,
+	}
+	data := &bytes.Buffer{}
+	if err := binary.Write(data, binary.LittleEndian, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	var actual foo
+	if err := Unmarshal(NewReader(data.Bytes()), &actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	var noLength struct {
+		A []byte
+	}
+	var tooWide struct {
+		A int8 `bitbuf:"bits=9"`
+	}
+	var badTag struct {
+		A int8 `bitbuf:"bits=x"`
+	}
+	var unsupported struct {
+		A map[string]int
+	}
+	var value struct {
+		A int8
+	}
+
+	testCases := []interface{}{&noLength, &tooWide, &badTag, &unsupported, value, nil}
+	for _, tc := range testCases {
+		if err := Unmarshal(NewReader(make([]byte, 8)), tc); err == nil {
+			t.Errorf("expected unmarshal of %T to fail", tc)
+		}
+	}
+
+	if err := Unmarshal(NewReader(nil), &value); err == nil {
+		t.Error("expected unmarshal beyond end of buffer to fail")
+	}
+}
@@ -0,0 +1,165 @@
+package netmsg
+
+//go:generate go run github.com/galaco/bitbuf/cmd/bitbufgen -type NOP,Disconnect,File,Tick,StringCmd,SetConVar,SignonState,Print,ServerInfo,SetPause,SetView,FixAngle,CrosshairAngle,Prefetch,GetCvarValue -output messages_bitbuf.go
+
+// Messages with a fixed layout are declared here, and their DecodeBits and EncodeBits
+// methods generated by bitbufgen. Messages with conditional fields or payloads are in payload.go.
+
+// NOP does nothing
+type NOP struct{}
+
+// Type returns NetNOP
+func (msg *NOP) Type() Type { return NetNOP }
+
+// Disconnect is sent when either side disconnects
+type Disconnect struct {
+	Reason string
+}
+
+// Type returns NetDisconnect
+func (msg *Disconnect) Type() Type { return NetDisconnect }
+
+// File requests, or denies a request for, a file
+type File struct {
+	TransferID uint32
+	Filename   string
+	Requested  bool
+}
+
+// Type returns NetFile
+func (msg *File) Type() Type { return NetFile }
+
+// Tick marks the start of a new server tick
+type Tick struct {
+	Tick int32
+	// HostFrameTime and HostFrameTimeStdDev are in seconds, scaled by TickScaleUp
+	HostFrameTime       uint16
+	HostFrameTimeStdDev uint16
+}
+
+// Type returns NetTick
+func (msg *Tick) Type() Type { return NetTick }
+
+// HostFrameTimeSeconds returns the host frame time, in seconds
+func (msg *Tick) HostFrameTimeSeconds() float32 {
+	return float32(msg.HostFrameTime) / TickScaleUp
+}
+
+// HostFrameTimeStdDevSeconds returns the standard deviation of the host frame time, in seconds
+func (msg *Tick) HostFrameTimeStdDevSeconds() float32 {
+	return float32(msg.HostFrameTimeStdDev) / TickScaleUp
+}
+
+// StringCmd is a console command
+type StringCmd struct {
+	Command string
+}
+
+// Type returns NetStringCmd
+func (msg *StringCmd) Type() Type { return NetStringCmd }
+
+// ConVar is a single console variable
+type ConVar struct {
+	Name  string
+	Value string
+}
+
+// SetConVar sets one or more console variables
+type SetConVar struct {
+	ConVars []ConVar `bitbuf:"len=8"`
+}
+
+// Type returns NetSetConVar
+func (msg *SetConVar) Type() Type { return NetSetConVar }
+
+// SignonState reports progress through the connection process
+type SignonState struct {
+	SignonState uint8
+	SpawnCount  int32
+}
+
+// Type returns NetSignonState
+func (msg *SignonState) Type() Type { return NetSignonState }
+
+// Print prints text to the console
+type Print struct {
+	Text string
+}
+
+// Type returns SvcPrint
+func (msg *Print) Type() Type { return SvcPrint }
+
+// ServerInfo describes the server, and is the first message sent to a connecting client.
+// IsReplay is only sent by servers built with replay support, which all Source 2013
+// multiplayer games are.
+type ServerInfo struct {
+	Protocol     int16
+	ServerCount  int32
+	IsHLTV       bool
+	IsDedicated  bool
+	ClientCRC    int32
+	MaxClasses   uint16
+	MapMD5       [16]byte
+	PlayerSlot   uint8
+	MaxClients   uint8
+	TickInterval float32
+	OS           uint8
+	GameDir      string
+	MapName      string
+	SkyName      string
+	HostName     string
+	IsReplay     bool
+}
+
+// Type returns SvcServerInfo
+func (msg *ServerInfo) Type() Type { return SvcServerInfo }
+
+// SetPause pauses or unpauses the game
+type SetPause struct {
+	Paused bool
+}
+
+// Type returns SvcSetPause
+func (msg *SetPause) Type() Type { return SvcSetPause }
+
+// SetView sets the entity the client views from
+type SetView struct {
+	EntityIndex uint16 `bitbuf:"bits=11"`
+}
+
+// Type returns SvcSetView
+func (msg *SetView) Type() Type { return SvcSetView }
+
+// FixAngle sets the view angles of the client
+type FixAngle struct {
+	Relative bool
+	Angle    [3]float32 `bitbuf:"angle=16"`
+}
+
+// Type returns SvcFixAngle
+func (msg *FixAngle) Type() Type { return SvcFixAngle }
+
+// CrosshairAngle sets the angle of the client crosshair
+type CrosshairAngle struct {
+	Angle [3]float32 `bitbuf:"angle=16"`
+}
+
+// Type returns SvcCrosshairAngle
+func (msg *CrosshairAngle) Type() Type { return SvcCrosshairAngle }
+
+// Prefetch asks the client to precache a sound
+type Prefetch struct {
+	SoundIndex uint16 `bitbuf:"bits=14"`
+}
+
+// Type returns SvcPrefetch
+func (msg *Prefetch) Type() Type { return SvcPrefetch }
+
+// GetCvarValue requests the value of a client console variable
+type GetCvarValue struct {
+	Cookie   int32
+	CvarName string
+}
+
+// Type returns SvcGetCvarValue
+func (msg *GetCvarValue) Type() Type { return SvcGetCvarValue }
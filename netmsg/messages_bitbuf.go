@@ -0,0 +1,577 @@
+// Code generated by bitbufgen; DO NOT EDIT.
+
+package netmsg
+
+import (
+	"fmt"
+
+	"github.com/galaco/bitbuf"
+)
+
+// DecodeBits reads NOP from buf
+func (v *NOP) DecodeBits(buf *bitbuf.Reader) error {
+	return nil
+}
+
+// EncodeBits writes NOP to buf
+func (v *NOP) EncodeBits(buf *bitbuf.Writer) error {
+	return nil
+}
+
+// DecodeBits reads Disconnect from buf
+func (v *Disconnect) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.Reason = x
+	}
+	return nil
+}
+
+// EncodeBits writes Disconnect to buf
+func (v *Disconnect) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteString(v.Reason + "\x00"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads File from buf
+func (v *File) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.TransferID = x
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.Filename = x
+	}
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.Requested = x
+	}
+	return nil
+}
+
+// EncodeBits writes File to buf
+func (v *File) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.TransferID), 32); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.Filename + "\x00"); err != nil {
+		return err
+	}
+	if err := buf.WriteOneBit(v.Requested); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads Tick from buf
+func (v *Tick) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.Tick = int32(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(16)
+		if err != nil {
+			return err
+		}
+		v.HostFrameTime = uint16(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(16)
+		if err != nil {
+			return err
+		}
+		v.HostFrameTimeStdDev = uint16(x)
+	}
+	return nil
+}
+
+// EncodeBits writes Tick to buf
+func (v *Tick) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.Tick), 32); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.HostFrameTime), 16); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.HostFrameTimeStdDev), 16); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads StringCmd from buf
+func (v *StringCmd) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.Command = x
+	}
+	return nil
+}
+
+// EncodeBits writes StringCmd to buf
+func (v *StringCmd) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteString(v.Command + "\x00"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads SetConVar from buf
+func (v *SetConVar) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		n, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		if err := buf.CheckAvailable(uint(n)); err != nil {
+			return err
+		}
+		v.ConVars = make([]ConVar, n)
+	}
+	for i0 := range v.ConVars {
+		if err := v.ConVars[i0].DecodeBits(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeBits writes SetConVar to buf
+func (v *SetConVar) EncodeBits(buf *bitbuf.Writer) error {
+	if uint64(len(v.ConVars)) >= 1<<8 {
+		return fmt.Errorf("bitbuf: %d elements do not fit in a 8 bit length", len(v.ConVars))
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(len(v.ConVars)), 8); err != nil {
+		return err
+	}
+	for i0 := range v.ConVars {
+		if err := v.ConVars[i0].EncodeBits(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBits reads SignonState from buf
+func (v *SignonState) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		v.SignonState = uint8(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.SpawnCount = int32(x)
+	}
+	return nil
+}
+
+// EncodeBits writes SignonState to buf
+func (v *SignonState) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.SignonState), 8); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.SpawnCount), 32); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads Print from buf
+func (v *Print) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.Text = x
+	}
+	return nil
+}
+
+// EncodeBits writes Print to buf
+func (v *Print) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteString(v.Text + "\x00"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads ServerInfo from buf
+func (v *ServerInfo) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(16)
+		if err != nil {
+			return err
+		}
+		v.Protocol = int16(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.ServerCount = int32(x)
+	}
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.IsHLTV = x
+	}
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.IsDedicated = x
+	}
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.ClientCRC = int32(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(16)
+		if err != nil {
+			return err
+		}
+		v.MaxClasses = uint16(x)
+	}
+	for i0 := range v.MapMD5 {
+		{
+			x, err := buf.ReadUint32Bits(8)
+			if err != nil {
+				return err
+			}
+			v.MapMD5[i0] = byte(x)
+		}
+	}
+	{
+		x, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		v.PlayerSlot = uint8(x)
+	}
+	{
+		x, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		v.MaxClients = uint8(x)
+	}
+	{
+		x, err := buf.ReadFloat32()
+		if err != nil {
+			return err
+		}
+		v.TickInterval = x
+	}
+	{
+		x, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return err
+		}
+		v.OS = uint8(x)
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.GameDir = x
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.MapName = x
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.SkyName = x
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.HostName = x
+	}
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.IsReplay = x
+	}
+	return nil
+}
+
+// EncodeBits writes ServerInfo to buf
+func (v *ServerInfo) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.Protocol), 16); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.ServerCount), 32); err != nil {
+		return err
+	}
+	if err := buf.WriteOneBit(v.IsHLTV); err != nil {
+		return err
+	}
+	if err := buf.WriteOneBit(v.IsDedicated); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.ClientCRC), 32); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.MaxClasses), 16); err != nil {
+		return err
+	}
+	for i0 := range v.MapMD5 {
+		if err := buf.WriteUnsignedBitInt32(uint32(v.MapMD5[i0]), 8); err != nil {
+			return err
+		}
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.PlayerSlot), 8); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.MaxClients), 8); err != nil {
+		return err
+	}
+	if err := buf.WriteFloat32(v.TickInterval); err != nil {
+		return err
+	}
+	if err := buf.WriteUnsignedBitInt32(uint32(v.OS), 8); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.GameDir + "\x00"); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.MapName + "\x00"); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.SkyName + "\x00"); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.HostName + "\x00"); err != nil {
+		return err
+	}
+	if err := buf.WriteOneBit(v.IsReplay); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads SetPause from buf
+func (v *SetPause) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.Paused = x
+	}
+	return nil
+}
+
+// EncodeBits writes SetPause to buf
+func (v *SetPause) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteOneBit(v.Paused); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads SetView from buf
+func (v *SetView) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(11)
+		if err != nil {
+			return err
+		}
+		v.EntityIndex = uint16(x)
+	}
+	return nil
+}
+
+// EncodeBits writes SetView to buf
+func (v *SetView) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.EntityIndex), 11); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads FixAngle from buf
+func (v *FixAngle) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadOneBit()
+		if err != nil {
+			return err
+		}
+		v.Relative = x
+	}
+	for i0 := range v.Angle {
+		{
+			x, err := buf.ReadBitAngle(16)
+			if err != nil {
+				return err
+			}
+			v.Angle[i0] = x
+		}
+	}
+	return nil
+}
+
+// EncodeBits writes FixAngle to buf
+func (v *FixAngle) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteOneBit(v.Relative); err != nil {
+		return err
+	}
+	for i0 := range v.Angle {
+		if err := buf.WriteBitAngle(float32(v.Angle[i0]), 16); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBits reads CrosshairAngle from buf
+func (v *CrosshairAngle) DecodeBits(buf *bitbuf.Reader) error {
+	for i0 := range v.Angle {
+		{
+			x, err := buf.ReadBitAngle(16)
+			if err != nil {
+				return err
+			}
+			v.Angle[i0] = x
+		}
+	}
+	return nil
+}
+
+// EncodeBits writes CrosshairAngle to buf
+func (v *CrosshairAngle) EncodeBits(buf *bitbuf.Writer) error {
+	for i0 := range v.Angle {
+		if err := buf.WriteBitAngle(float32(v.Angle[i0]), 16); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBits reads Prefetch from buf
+func (v *Prefetch) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(14)
+		if err != nil {
+			return err
+		}
+		v.SoundIndex = uint16(x)
+	}
+	return nil
+}
+
+// EncodeBits writes Prefetch to buf
+func (v *Prefetch) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.SoundIndex), 14); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads GetCvarValue from buf
+func (v *GetCvarValue) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadUint32Bits(32)
+		if err != nil {
+			return err
+		}
+		v.Cookie = int32(x)
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.CvarName = x
+	}
+	return nil
+}
+
+// EncodeBits writes GetCvarValue to buf
+func (v *GetCvarValue) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteUnsignedBitInt32(uint32(v.Cookie), 32); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.CvarName + "\x00"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeBits reads ConVar from buf
+func (v *ConVar) DecodeBits(buf *bitbuf.Reader) error {
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.Name = x
+	}
+	{
+		x, err := buf.ReadString(0)
+		if err != nil {
+			return err
+		}
+		v.Value = x
+	}
+	return nil
+}
+
+// EncodeBits writes ConVar to buf
+func (v *ConVar) EncodeBits(buf *bitbuf.Writer) error {
+	if err := buf.WriteString(v.Name + "\x00"); err != nil {
+		return err
+	}
+	if err := buf.WriteString(v.Value + "\x00"); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,475 @@
+// Code generated by bitbufgen; DO NOT EDIT.
+
+package netmsg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+)
+
+func TestNOP_BitsRoundTrip(t *testing.T) {
+	expected := NOP{}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual NOP
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestDisconnect_BitsRoundTrip(t *testing.T) {
+	expected := Disconnect{Reason: "bitbuf"}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual Disconnect
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestFile_BitsRoundTrip(t *testing.T) {
+	expected := File{TransferID: uint32(1515870810), Filename: "bitbuf", Requested: true}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual File
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestTick_BitsRoundTrip(t *testing.T) {
+	expected := Tick{Tick: int32(-1073741824), HostFrameTime: uint16(23130), HostFrameTimeStdDev: uint16(23130)}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual Tick
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestStringCmd_BitsRoundTrip(t *testing.T) {
+	expected := StringCmd{Command: "bitbuf"}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual StringCmd
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestSetConVar_BitsRoundTrip(t *testing.T) {
+	expected := SetConVar{ConVars: []ConVar{ConVar{Name: "bitbuf", Value: "bitbuf"}, ConVar{Name: "bitbuf", Value: "bitbuf"}}}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual SetConVar
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestSignonState_BitsRoundTrip(t *testing.T) {
+	expected := SignonState{SignonState: uint8(90), SpawnCount: int32(-1073741824)}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual SignonState
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestPrint_BitsRoundTrip(t *testing.T) {
+	expected := Print{Text: "bitbuf"}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual Print
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestServerInfo_BitsRoundTrip(t *testing.T) {
+	expected := ServerInfo{Protocol: int16(-16384), ServerCount: int32(-1073741824), IsHLTV: true, IsDedicated: true, ClientCRC: int32(-1073741824), MaxClasses: uint16(23130), MapMD5: [16]byte{byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90), byte(90)}, PlayerSlot: uint8(90), MaxClients: uint8(90), TickInterval: float32(2106.25), OS: uint8(90), GameDir: "bitbuf", MapName: "bitbuf", SkyName: "bitbuf", HostName: "bitbuf", IsReplay: true}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual ServerInfo
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestSetPause_BitsRoundTrip(t *testing.T) {
+	expected := SetPause{Paused: true}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual SetPause
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestSetView_BitsRoundTrip(t *testing.T) {
+	expected := SetView{EntityIndex: uint16(722)}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual SetView
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestFixAngle_BitsRoundTrip(t *testing.T) {
+	expected := FixAngle{Relative: true, Angle: [3]float32{float32(90), float32(90), float32(90)}}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual FixAngle
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestCrosshairAngle_BitsRoundTrip(t *testing.T) {
+	expected := CrosshairAngle{Angle: [3]float32{float32(90), float32(90), float32(90)}}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual CrosshairAngle
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestPrefetch_BitsRoundTrip(t *testing.T) {
+	expected := Prefetch{SoundIndex: uint16(5782)}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual Prefetch
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestGetCvarValue_BitsRoundTrip(t *testing.T) {
+	expected := GetCvarValue{Cookie: int32(-1073741824), CvarName: "bitbuf"}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual GetCvarValue
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestConVar_BitsRoundTrip(t *testing.T) {
+	expected := ConVar{Name: "bitbuf", Value: "bitbuf"}
+
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.EncodeBits(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reflected := bitbuf.NewGrowableWriter(0, 0)
+	if err := bitbuf.Marshal(reflected, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(writer.Data(), reflected.Data()) {
+		t.Errorf("expected: %v, but received: %v", reflected.Data(), writer.Data())
+	}
+
+	var actual ConVar
+	reader := bitbuf.NewReader(writer.Data())
+	if err := actual.DecodeBits(reader); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %+v, but received: %+v", expected, actual)
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
@@ -0,0 +1,149 @@
+// Package netmsg decodes the Source engine (Source 2013, network protocol 24) net messages
+// found in demo packets and live server traffic.
+//
+// Each message is prefixed by its Type, in TypeBits bits. Messages are not length prefixed,
+// so a message of an unknown type can't be skipped, and ends decoding of the packet unless
+// a handler is registered for it with Registry.OnUnknown.
+package netmsg
+
+import (
+	"fmt"
+
+	"github.com/galaco/bitbuf"
+)
+
+// Bit widths of fields shared between messages, matching the Source engine
+const (
+	// TypeBits is the width of the Type preceding each message (NETMSG_TYPE_BITS)
+	TypeBits = 6
+	// MaxEdictBits is the width of an entity index
+	MaxEdictBits = 11
+	// MaxServerClassBits is the width of a server class ID
+	MaxServerClassBits = 9
+	// NetMaxPayloadBits is the width of a payload length, in bits
+	NetMaxPayloadBits = 17
+	// DeltaSizeBits is the width of a packet entities or string table payload length, in bits
+	DeltaSizeBits = 20
+	// MaxTableBits is the width of a string table ID
+	MaxTableBits = 5
+	// MaxUserDataBits is the width of a fixed string table userdata size
+	MaxUserDataBits = 12
+	// MaxDecalIndexBits is the width of a decal texture index
+	MaxDecalIndexBits = 9
+	// ModelIndexBits is the width of a model index
+	ModelIndexBits = 13
+	// MaxSoundIndexBits is the width of a sound index
+	MaxSoundIndexBits = 14
+	// EventIndexBits is the width of a temp entity count
+	EventIndexBits = 8
+	// MaxEventBits is the width of a game event count
+	MaxEventBits = 9
+	// LengthBits is the width of a user message, entity message or game event length, in bits
+	LengthBits = 11
+	// TickScaleUp is the scale applied to host frame times in Tick messages
+	TickScaleUp = 100000
+)
+
+// Type identifies a message
+type Type uint8
+
+// Source 2013 message types
+const (
+	NetNOP               Type = 0
+	NetDisconnect        Type = 1
+	NetFile              Type = 2
+	NetTick              Type = 3
+	NetStringCmd         Type = 4
+	NetSetConVar         Type = 5
+	NetSignonState       Type = 6
+	SvcPrint             Type = 7
+	SvcServerInfo        Type = 8
+	SvcSendTable         Type = 9
+	SvcClassInfo         Type = 10
+	SvcSetPause          Type = 11
+	SvcCreateStringTable Type = 12
+	SvcUpdateStringTable Type = 13
+	SvcVoiceInit         Type = 14
+	SvcVoiceData         Type = 15
+	SvcSounds            Type = 17
+	SvcSetView           Type = 18
+	SvcFixAngle          Type = 19
+	SvcCrosshairAngle    Type = 20
+	SvcBSPDecal          Type = 21
+	SvcUserMessage       Type = 23
+	SvcEntityMessage     Type = 24
+	SvcGameEvent         Type = 25
+	SvcPacketEntities    Type = 26
+	SvcTempEntities      Type = 27
+	SvcPrefetch          Type = 28
+	SvcMenu              Type = 29
+	SvcGameEventList     Type = 30
+	SvcGetCvarValue      Type = 31
+	SvcCmdKeyValues      Type = 32
+)
+
+var typeNames = map[Type]string{
+	NetNOP:               "net_NOP",
+	NetDisconnect:        "net_Disconnect",
+	NetFile:              "net_File",
+	NetTick:              "net_Tick",
+	NetStringCmd:         "net_StringCmd",
+	NetSetConVar:         "net_SetConVar",
+	NetSignonState:       "net_SignonState",
+	SvcPrint:             "svc_Print",
+	SvcServerInfo:        "svc_ServerInfo",
+	SvcSendTable:         "svc_SendTable",
+	SvcClassInfo:         "svc_ClassInfo",
+	SvcSetPause:          "svc_SetPause",
+	SvcCreateStringTable: "svc_CreateStringTable",
+	SvcUpdateStringTable: "svc_UpdateStringTable",
+	SvcVoiceInit:         "svc_VoiceInit",
+	SvcVoiceData:         "svc_VoiceData",
+	SvcSounds:            "svc_Sounds",
+	SvcSetView:           "svc_SetView",
+	SvcFixAngle:          "svc_FixAngle",
+	SvcCrosshairAngle:    "svc_CrosshairAngle",
+	SvcBSPDecal:          "svc_BSPDecal",
+	SvcUserMessage:       "svc_UserMessage",
+	SvcEntityMessage:     "svc_EntityMessage",
+	SvcGameEvent:         "svc_GameEvent",
+	SvcPacketEntities:    "svc_PacketEntities",
+	SvcTempEntities:      "svc_TempEntities",
+	SvcPrefetch:          "svc_Prefetch",
+	SvcMenu:              "svc_Menu",
+	SvcGameEventList:     "svc_GameEventList",
+	SvcGetCvarValue:      "svc_GetCvarValue",
+	SvcCmdKeyValues:      "svc_CmdKeyValues",
+}
+
+func (msgType Type) String() string {
+	if name, ok := typeNames[msgType]; ok {
+		return name
+	}
+	return fmt.Sprintf("Type(%d)", uint8(msgType))
+}
+
+// Message is a single net message
+type Message interface {
+	// Type returns the type that precedes the message
+	Type() Type
+	// DecodeBits reads the message body, which follows its type
+	DecodeBits(buf *bitbuf.Reader) error
+	// EncodeBits writes the message body, which follows its type
+	EncodeBits(writer *bitbuf.Writer) error
+}
+
+// Skipper is implemented by messages that can be passed over more cheaply than decoding them,
+// typically by reading only the length of their payload.
+type Skipper interface {
+	// Skip reads past the message body, which follows its type
+	Skip(buf *bitbuf.Reader) error
+}
+
+// WriteMessage writes msg to writer, preceded by its type.
+func WriteMessage(writer *bitbuf.Writer, msg Message) error {
+	if err := writer.WriteUnsignedBitInt32(uint32(msg.Type()), TypeBits); err != nil {
+		return err
+	}
+	return msg.EncodeBits(writer)
+}
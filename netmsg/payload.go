@@ -0,0 +1,899 @@
+package netmsg
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/galaco/bitbuf"
+)
+
+// log2 returns floor(log2(value)), or 0 for 0, as Q_log2 does
+func log2(value uint32) uint {
+	if value == 0 {
+		return 0
+	}
+	return uint(bits.Len32(value)) - 1
+}
+
+// writeBounded writes value in numBits bits, failing if it doesn't fit
+func writeBounded(writer *bitbuf.Writer, value uint32, numBits uint) error {
+	if uint64(value) >= uint64(1)<<numBits {
+		return fmt.Errorf("netmsg value %d does not fit in %d bits", value, numBits)
+	}
+	return writer.WriteUnsignedBitInt32(value, numBits)
+}
+
+// readPayload reads a length in lengthBits bits, followed by that many bits of data
+func readPayload(buf *bitbuf.Reader, lengthBits uint) (uint32, []byte, error) {
+	length, err := buf.ReadUint32Bits(lengthBits)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := buf.ReadBits(uint(length))
+	return length, data, err
+}
+
+// writePayload writes length in lengthBits bits, followed by length bits of data
+func writePayload(writer *bitbuf.Writer, length uint32, data []byte, lengthBits uint) error {
+	if err := writeBounded(writer, length, lengthBits); err != nil {
+		return err
+	}
+	return writer.WriteBits(data, uint(length))
+}
+
+// skipPayload reads a length in lengthBits bits, and skips that many bits of data
+func skipPayload(buf *bitbuf.Reader, lengthBits uint) error {
+	length, err := buf.ReadUint32Bits(lengthBits)
+	if err != nil {
+		return err
+	}
+	return buf.SkipBits(uint(length))
+}
+
+// SendTable contains a single send table
+type SendTable struct {
+	NeedsDecoder bool
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcSendTable
+func (msg *SendTable) Type() Type { return SvcSendTable }
+
+// DecodeBits reads SendTable from buf
+func (msg *SendTable) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.NeedsDecoder, err = buf.ReadOneBit(); err != nil {
+		return err
+	}
+	msg.Length, msg.Data, err = readPayload(buf, 16)
+	return err
+}
+
+// EncodeBits writes SendTable to writer
+func (msg *SendTable) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteOneBit(msg.NeedsDecoder); err != nil {
+		return err
+	}
+	return writePayload(writer, msg.Length, msg.Data, 16)
+}
+
+// Skip reads past a SendTable in buf
+func (msg *SendTable) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(1); err != nil {
+		return err
+	}
+	return skipPayload(buf, 16)
+}
+
+// Class maps a server class ID to its name and data table
+type Class struct {
+	ClassID       uint32
+	ClassName     string
+	DataTableName string
+}
+
+// ClassInfo lists the server classes.
+// If CreateOnClient is set, the client builds the list from its own data tables instead.
+type ClassInfo struct {
+	NumClasses     uint16
+	CreateOnClient bool
+	Classes        []Class
+}
+
+// Type returns SvcClassInfo
+func (msg *ClassInfo) Type() Type { return SvcClassInfo }
+
+// DecodeBits reads ClassInfo from buf
+func (msg *ClassInfo) DecodeBits(buf *bitbuf.Reader) error {
+	numClasses, err := buf.ReadUint32Bits(16)
+	if err != nil {
+		return err
+	}
+	msg.NumClasses = uint16(numClasses)
+	if msg.CreateOnClient, err = buf.ReadOneBit(); err != nil || msg.CreateOnClient {
+		msg.Classes = nil
+		return err
+	}
+
+	idBits := log2(numClasses) + 1
+	msg.Classes = make([]Class, numClasses)
+	for i := range msg.Classes {
+		class := &msg.Classes[i]
+		if class.ClassID, err = buf.ReadUint32Bits(idBits); err != nil {
+			return err
+		}
+		if class.ClassName, err = buf.ReadString(0); err != nil {
+			return err
+		}
+		if class.DataTableName, err = buf.ReadString(0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncodeBits writes ClassInfo to writer
+func (msg *ClassInfo) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteUnsignedBitInt32(uint32(msg.NumClasses), 16); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(msg.CreateOnClient); err != nil || msg.CreateOnClient {
+		return err
+	}
+	if len(msg.Classes) != int(msg.NumClasses) {
+		return fmt.Errorf("netmsg %s has %d classes, but NumClasses is %d", msg.Type(), len(msg.Classes), msg.NumClasses)
+	}
+
+	idBits := log2(uint32(msg.NumClasses)) + 1
+	for _, class := range msg.Classes {
+		if err := writer.WriteUnsignedBitInt32(class.ClassID, idBits); err != nil {
+			return err
+		}
+		if err := writer.WriteCString(class.ClassName); err != nil {
+			return err
+		}
+		if err := writer.WriteCString(class.DataTableName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateStringTable creates a string table, and populates its initial entries
+type CreateStringTable struct {
+	// IsFilenames is sent as a ':' prefix to the table name
+	IsFilenames bool
+	Name        string
+	MaxEntries  uint16
+	NumEntries  uint32
+	// Length is the length of Data, in bits
+	Length            uint32
+	UserDataFixedSize bool
+	UserDataSize      uint32
+	UserDataSizeBits  uint32
+	DataCompressed    bool
+	Data              []byte
+}
+
+// Type returns SvcCreateStringTable
+func (msg *CreateStringTable) Type() Type { return SvcCreateStringTable }
+
+// DecodeBits reads CreateStringTable from buf
+func (msg *CreateStringTable) DecodeBits(buf *bitbuf.Reader) error {
+	if err := msg.decodeHeader(buf); err != nil {
+		return err
+	}
+	var err error
+	msg.Data, err = buf.ReadBits(uint(msg.Length))
+	return err
+}
+
+// decodeHeader reads every field of a CreateStringTable except Data
+func (msg *CreateStringTable) decodeHeader(buf *bitbuf.Reader) (err error) {
+	prefix, err := buf.PeekUint32Bits(8)
+	if err != nil {
+		return err
+	}
+	if msg.IsFilenames = prefix == ':'; msg.IsFilenames {
+		if err = buf.SkipBits(8); err != nil {
+			return err
+		}
+	}
+	if msg.Name, err = buf.ReadString(0); err != nil {
+		return err
+	}
+	maxEntries, err := buf.ReadUint32Bits(16)
+	if err != nil {
+		return err
+	}
+	msg.MaxEntries = uint16(maxEntries)
+	if msg.NumEntries, err = buf.ReadUint32Bits(log2(maxEntries) + 1); err != nil {
+		return err
+	}
+	if msg.Length, err = buf.ReadUint32Bits(DeltaSizeBits); err != nil {
+		return err
+	}
+	if msg.UserDataFixedSize, err = buf.ReadOneBit(); err != nil {
+		return err
+	}
+	msg.UserDataSize, msg.UserDataSizeBits = 0, 0
+	if msg.UserDataFixedSize {
+		if msg.UserDataSize, err = buf.ReadUint32Bits(MaxUserDataBits); err != nil {
+			return err
+		}
+		if msg.UserDataSizeBits, err = buf.ReadUint32Bits(4); err != nil {
+			return err
+		}
+	}
+	msg.DataCompressed, err = buf.ReadOneBit()
+	return err
+}
+
+// EncodeBits writes CreateStringTable to writer
+func (msg *CreateStringTable) EncodeBits(writer *bitbuf.Writer) error {
+	if msg.IsFilenames {
+		if err := writer.WriteByte(':'); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteCString(msg.Name); err != nil {
+		return err
+	}
+	if err := writer.WriteUnsignedBitInt32(uint32(msg.MaxEntries), 16); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, msg.NumEntries, log2(uint32(msg.MaxEntries))+1); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, msg.Length, DeltaSizeBits); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(msg.UserDataFixedSize); err != nil {
+		return err
+	}
+	if msg.UserDataFixedSize {
+		if err := writeBounded(writer, msg.UserDataSize, MaxUserDataBits); err != nil {
+			return err
+		}
+		if err := writeBounded(writer, msg.UserDataSizeBits, 4); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteOneBit(msg.DataCompressed); err != nil {
+		return err
+	}
+	return writer.WriteBits(msg.Data, uint(msg.Length))
+}
+
+// Skip reads past a CreateStringTable in buf
+func (msg *CreateStringTable) Skip(buf *bitbuf.Reader) error {
+	var header CreateStringTable
+	if err := header.decodeHeader(buf); err != nil {
+		return err
+	}
+	return buf.SkipBits(uint(header.Length))
+}
+
+// UpdateStringTable changes entries of an existing string table
+type UpdateStringTable struct {
+	TableID        uint32
+	ChangedEntries uint16
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcUpdateStringTable
+func (msg *UpdateStringTable) Type() Type { return SvcUpdateStringTable }
+
+// DecodeBits reads UpdateStringTable from buf
+func (msg *UpdateStringTable) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.TableID, err = buf.ReadUint32Bits(MaxTableBits); err != nil {
+		return err
+	}
+	multipleChanged, err := buf.ReadOneBit()
+	if err != nil {
+		return err
+	}
+	msg.ChangedEntries = 1
+	if multipleChanged {
+		changedEntries, err := buf.ReadUint32Bits(16)
+		if err != nil {
+			return err
+		}
+		msg.ChangedEntries = uint16(changedEntries)
+	}
+	msg.Length, msg.Data, err = readPayload(buf, DeltaSizeBits)
+	return err
+}
+
+// EncodeBits writes UpdateStringTable to writer
+func (msg *UpdateStringTable) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writeBounded(writer, msg.TableID, MaxTableBits); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(msg.ChangedEntries != 1); err != nil {
+		return err
+	}
+	if msg.ChangedEntries != 1 {
+		if err := writer.WriteUnsignedBitInt32(uint32(msg.ChangedEntries), 16); err != nil {
+			return err
+		}
+	}
+	return writePayload(writer, msg.Length, msg.Data, DeltaSizeBits)
+}
+
+// Skip reads past an UpdateStringTable in buf
+func (msg *UpdateStringTable) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(MaxTableBits); err != nil {
+		return err
+	}
+	multipleChanged, err := buf.ReadOneBit()
+	if err != nil {
+		return err
+	}
+	if multipleChanged {
+		if err := buf.SkipBits(16); err != nil {
+			return err
+		}
+	}
+	return skipPayload(buf, DeltaSizeBits)
+}
+
+// VoiceInit describes the voice codec in use
+type VoiceInit struct {
+	Codec   string
+	Quality uint8
+	// SampleRate is only sent when Quality is 255
+	SampleRate uint16
+}
+
+// Type returns SvcVoiceInit
+func (msg *VoiceInit) Type() Type { return SvcVoiceInit }
+
+// DecodeBits reads VoiceInit from buf
+func (msg *VoiceInit) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.Codec, err = buf.ReadString(0); err != nil {
+		return err
+	}
+	if msg.Quality, err = buf.ReadUint8(); err != nil {
+		return err
+	}
+	msg.SampleRate = 0
+	if msg.Quality == 255 {
+		msg.SampleRate, err = buf.ReadUint16()
+	}
+	return err
+}
+
+// EncodeBits writes VoiceInit to writer
+func (msg *VoiceInit) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteCString(msg.Codec); err != nil {
+		return err
+	}
+	if err := writer.WriteUint8(msg.Quality); err != nil {
+		return err
+	}
+	if msg.Quality == 255 {
+		return writer.WriteUint16(msg.SampleRate)
+	}
+	return nil
+}
+
+// VoiceData carries compressed voice data from a client
+type VoiceData struct {
+	FromClient uint8
+	Proximity  uint8
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcVoiceData
+func (msg *VoiceData) Type() Type { return SvcVoiceData }
+
+// DecodeBits reads VoiceData from buf
+func (msg *VoiceData) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.FromClient, err = buf.ReadUint8(); err != nil {
+		return err
+	}
+	if msg.Proximity, err = buf.ReadUint8(); err != nil {
+		return err
+	}
+	msg.Length, msg.Data, err = readPayload(buf, 16)
+	return err
+}
+
+// EncodeBits writes VoiceData to writer
+func (msg *VoiceData) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteUint8(msg.FromClient); err != nil {
+		return err
+	}
+	if err := writer.WriteUint8(msg.Proximity); err != nil {
+		return err
+	}
+	return writePayload(writer, msg.Length, msg.Data, 16)
+}
+
+// Skip reads past a VoiceData in buf
+func (msg *VoiceData) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(16); err != nil {
+		return err
+	}
+	return skipPayload(buf, 16)
+}
+
+// Sounds carries one reliable sound, or a batch of unreliable sounds
+type Sounds struct {
+	Reliable bool
+	// NumSounds is always 1 for reliable sounds
+	NumSounds uint8
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcSounds
+func (msg *Sounds) Type() Type { return SvcSounds }
+
+// lengthBits returns the width of Length
+func (msg *Sounds) lengthBits() uint {
+	if msg.Reliable {
+		return 8
+	}
+	return 16
+}
+
+// DecodeBits reads Sounds from buf
+func (msg *Sounds) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.Reliable, err = buf.ReadOneBit(); err != nil {
+		return err
+	}
+	msg.NumSounds = 1
+	if !msg.Reliable {
+		if msg.NumSounds, err = buf.ReadUint8(); err != nil {
+			return err
+		}
+	}
+	msg.Length, msg.Data, err = readPayload(buf, msg.lengthBits())
+	return err
+}
+
+// EncodeBits writes Sounds to writer
+func (msg *Sounds) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteOneBit(msg.Reliable); err != nil {
+		return err
+	}
+	if !msg.Reliable {
+		if err := writer.WriteUint8(msg.NumSounds); err != nil {
+			return err
+		}
+	}
+	return writePayload(writer, msg.Length, msg.Data, msg.lengthBits())
+}
+
+// Skip reads past a Sounds in buf
+func (msg *Sounds) Skip(buf *bitbuf.Reader) error {
+	var header Sounds
+	var err error
+	if header.Reliable, err = buf.ReadOneBit(); err != nil {
+		return err
+	}
+	if !header.Reliable {
+		if err := buf.SkipBits(8); err != nil {
+			return err
+		}
+	}
+	return skipPayload(buf, header.lengthBits())
+}
+
+// BSPDecal places a decal on the world, or on an entity
+type BSPDecal struct {
+	Position          [3]float32
+	DecalTextureIndex uint32
+	// EntityIndex and ModelIndex are only sent when EntityIndex is not 0
+	EntityIndex uint32
+	ModelIndex  uint32
+	LowPriority bool
+}
+
+// Type returns SvcBSPDecal
+func (msg *BSPDecal) Type() Type { return SvcBSPDecal }
+
+// DecodeBits reads BSPDecal from buf
+func (msg *BSPDecal) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.Position, err = buf.ReadBitVec3Coord(); err != nil {
+		return err
+	}
+	if msg.DecalTextureIndex, err = buf.ReadUint32Bits(MaxDecalIndexBits); err != nil {
+		return err
+	}
+	hasEntity, err := buf.ReadOneBit()
+	if err != nil {
+		return err
+	}
+	msg.EntityIndex, msg.ModelIndex = 0, 0
+	if hasEntity {
+		if msg.EntityIndex, err = buf.ReadUint32Bits(MaxEdictBits); err != nil {
+			return err
+		}
+		if msg.ModelIndex, err = buf.ReadUint32Bits(ModelIndexBits); err != nil {
+			return err
+		}
+	}
+	msg.LowPriority, err = buf.ReadOneBit()
+	return err
+}
+
+// EncodeBits writes BSPDecal to writer
+func (msg *BSPDecal) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteBitVec3Coord(msg.Position); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, msg.DecalTextureIndex, MaxDecalIndexBits); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(msg.EntityIndex != 0); err != nil {
+		return err
+	}
+	if msg.EntityIndex != 0 {
+		if err := writeBounded(writer, msg.EntityIndex, MaxEdictBits); err != nil {
+			return err
+		}
+		if err := writeBounded(writer, msg.ModelIndex, ModelIndexBits); err != nil {
+			return err
+		}
+	}
+	return writer.WriteOneBit(msg.LowPriority)
+}
+
+// UserMessage carries a game specific user message
+type UserMessage struct {
+	MsgType uint8
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcUserMessage
+func (msg *UserMessage) Type() Type { return SvcUserMessage }
+
+// DecodeBits reads UserMessage from buf
+func (msg *UserMessage) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.MsgType, err = buf.ReadUint8(); err != nil {
+		return err
+	}
+	msg.Length, msg.Data, err = readPayload(buf, LengthBits)
+	return err
+}
+
+// EncodeBits writes UserMessage to writer
+func (msg *UserMessage) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteUint8(msg.MsgType); err != nil {
+		return err
+	}
+	return writePayload(writer, msg.Length, msg.Data, LengthBits)
+}
+
+// Skip reads past a UserMessage in buf
+func (msg *UserMessage) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(8); err != nil {
+		return err
+	}
+	return skipPayload(buf, LengthBits)
+}
+
+// EntityMessage carries a message for a single entity
+type EntityMessage struct {
+	EntityIndex uint32
+	ClassID     uint32
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcEntityMessage
+func (msg *EntityMessage) Type() Type { return SvcEntityMessage }
+
+// DecodeBits reads EntityMessage from buf
+func (msg *EntityMessage) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.EntityIndex, err = buf.ReadUint32Bits(MaxEdictBits); err != nil {
+		return err
+	}
+	if msg.ClassID, err = buf.ReadUint32Bits(MaxServerClassBits); err != nil {
+		return err
+	}
+	msg.Length, msg.Data, err = readPayload(buf, LengthBits)
+	return err
+}
+
+// EncodeBits writes EntityMessage to writer
+func (msg *EntityMessage) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writeBounded(writer, msg.EntityIndex, MaxEdictBits); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, msg.ClassID, MaxServerClassBits); err != nil {
+		return err
+	}
+	return writePayload(writer, msg.Length, msg.Data, LengthBits)
+}
+
+// Skip reads past an EntityMessage in buf
+func (msg *EntityMessage) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(MaxEdictBits + MaxServerClassBits); err != nil {
+		return err
+	}
+	return skipPayload(buf, LengthBits)
+}
+
+// GameEvent carries a single serialized game event.
+// Decoding Data requires the descriptors sent in GameEventList.
+type GameEvent struct {
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcGameEvent
+func (msg *GameEvent) Type() Type { return SvcGameEvent }
+
+// DecodeBits reads GameEvent from buf
+func (msg *GameEvent) DecodeBits(buf *bitbuf.Reader) (err error) {
+	msg.Length, msg.Data, err = readPayload(buf, LengthBits)
+	return err
+}
+
+// EncodeBits writes GameEvent to writer
+func (msg *GameEvent) EncodeBits(writer *bitbuf.Writer) error {
+	return writePayload(writer, msg.Length, msg.Data, LengthBits)
+}
+
+// Skip reads past a GameEvent in buf
+func (msg *GameEvent) Skip(buf *bitbuf.Reader) error {
+	return skipPayload(buf, LengthBits)
+}
+
+// PacketEntities carries entity creations, updates and deletions,
+// optionally as a delta from an earlier tick.
+type PacketEntities struct {
+	MaxEntries uint32
+	IsDelta    bool
+	// DeltaFrom is the tick this update is relative to, or -1 if IsDelta is not set
+	DeltaFrom      int32
+	Baseline       bool
+	UpdatedEntries uint32
+	// Length is the length of Data, in bits
+	Length         uint32
+	UpdateBaseline bool
+	Data           []byte
+}
+
+// Type returns SvcPacketEntities
+func (msg *PacketEntities) Type() Type { return SvcPacketEntities }
+
+// DecodeBits reads PacketEntities from buf
+func (msg *PacketEntities) DecodeBits(buf *bitbuf.Reader) error {
+	if err := msg.decodeHeader(buf); err != nil {
+		return err
+	}
+	var err error
+	msg.Data, err = buf.ReadBits(uint(msg.Length))
+	return err
+}
+
+// decodeHeader reads every field of a PacketEntities except Data
+func (msg *PacketEntities) decodeHeader(buf *bitbuf.Reader) (err error) {
+	if msg.MaxEntries, err = buf.ReadUint32Bits(MaxEdictBits); err != nil {
+		return err
+	}
+	if msg.IsDelta, err = buf.ReadOneBit(); err != nil {
+		return err
+	}
+	msg.DeltaFrom = -1
+	if msg.IsDelta {
+		if msg.DeltaFrom, err = buf.ReadInt32(); err != nil {
+			return err
+		}
+	}
+	if msg.Baseline, err = buf.ReadOneBit(); err != nil {
+		return err
+	}
+	if msg.UpdatedEntries, err = buf.ReadUint32Bits(MaxEdictBits); err != nil {
+		return err
+	}
+	if msg.Length, err = buf.ReadUint32Bits(DeltaSizeBits); err != nil {
+		return err
+	}
+	msg.UpdateBaseline, err = buf.ReadOneBit()
+	return err
+}
+
+// EncodeBits writes PacketEntities to writer
+func (msg *PacketEntities) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writeBounded(writer, msg.MaxEntries, MaxEdictBits); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(msg.IsDelta); err != nil {
+		return err
+	}
+	if msg.IsDelta {
+		if err := writer.WriteInt32(msg.DeltaFrom); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteOneBit(msg.Baseline); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, msg.UpdatedEntries, MaxEdictBits); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, msg.Length, DeltaSizeBits); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(msg.UpdateBaseline); err != nil {
+		return err
+	}
+	return writer.WriteBits(msg.Data, uint(msg.Length))
+}
+
+// Skip reads past a PacketEntities in buf
+func (msg *PacketEntities) Skip(buf *bitbuf.Reader) error {
+	var header PacketEntities
+	if err := header.decodeHeader(buf); err != nil {
+		return err
+	}
+	return buf.SkipBits(uint(header.Length))
+}
+
+// TempEntities carries a batch of temporary entity events
+type TempEntities struct {
+	NumEntries uint8
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcTempEntities
+func (msg *TempEntities) Type() Type { return SvcTempEntities }
+
+// DecodeBits reads TempEntities from buf
+func (msg *TempEntities) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.NumEntries, err = buf.ReadUint8(); err != nil {
+		return err
+	}
+	msg.Length, msg.Data, err = readPayload(buf, NetMaxPayloadBits)
+	return err
+}
+
+// EncodeBits writes TempEntities to writer
+func (msg *TempEntities) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteUint8(msg.NumEntries); err != nil {
+		return err
+	}
+	return writePayload(writer, msg.Length, msg.Data, NetMaxPayloadBits)
+}
+
+// Skip reads past a TempEntities in buf
+func (msg *TempEntities) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(8); err != nil {
+		return err
+	}
+	return skipPayload(buf, NetMaxPayloadBits)
+}
+
+// Menu displays a plugin menu, serialized as KeyValues
+type Menu struct {
+	MenuType uint16
+	Data     []byte
+}
+
+// Type returns SvcMenu
+func (msg *Menu) Type() Type { return SvcMenu }
+
+// DecodeBits reads Menu from buf
+func (msg *Menu) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.MenuType, err = buf.ReadUint16(); err != nil {
+		return err
+	}
+	length, err := buf.ReadUint16()
+	if err != nil {
+		return err
+	}
+	msg.Data, err = buf.ReadBytes(uint(length))
+	return err
+}
+
+// EncodeBits writes Menu to writer
+func (msg *Menu) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteUint16(msg.MenuType); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, uint32(len(msg.Data)), 16); err != nil {
+		return err
+	}
+	return writer.WriteBytes(msg.Data)
+}
+
+// Skip reads past a Menu in buf
+func (msg *Menu) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(16); err != nil {
+		return err
+	}
+	length, err := buf.ReadUint16()
+	if err != nil {
+		return err
+	}
+	return buf.SkipBytes(uint(length))
+}
+
+// GameEventList describes every game event the server can send
+type GameEventList struct {
+	NumEvents uint32
+	// Length is the length of Data, in bits
+	Length uint32
+	Data   []byte
+}
+
+// Type returns SvcGameEventList
+func (msg *GameEventList) Type() Type { return SvcGameEventList }
+
+// DecodeBits reads GameEventList from buf
+func (msg *GameEventList) DecodeBits(buf *bitbuf.Reader) (err error) {
+	if msg.NumEvents, err = buf.ReadUint32Bits(MaxEventBits); err != nil {
+		return err
+	}
+	msg.Length, msg.Data, err = readPayload(buf, DeltaSizeBits)
+	return err
+}
+
+// EncodeBits writes GameEventList to writer
+func (msg *GameEventList) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writeBounded(writer, msg.NumEvents, MaxEventBits); err != nil {
+		return err
+	}
+	return writePayload(writer, msg.Length, msg.Data, DeltaSizeBits)
+}
+
+// Skip reads past a GameEventList in buf
+func (msg *GameEventList) Skip(buf *bitbuf.Reader) error {
+	if err := buf.SkipBits(MaxEventBits); err != nil {
+		return err
+	}
+	return skipPayload(buf, DeltaSizeBits)
+}
+
+// CmdKeyValues carries a serialized KeyValues command
+type CmdKeyValues struct {
+	Data []byte
+}
+
+// Type returns SvcCmdKeyValues
+func (msg *CmdKeyValues) Type() Type { return SvcCmdKeyValues }
+
+// DecodeBits reads CmdKeyValues from buf
+func (msg *CmdKeyValues) DecodeBits(buf *bitbuf.Reader) error {
+	length, err := buf.ReadUint32()
+	if err != nil {
+		return err
+	}
+	msg.Data, err = buf.ReadBytes(uint(length))
+	return err
+}
+
+// EncodeBits writes CmdKeyValues to writer
+func (msg *CmdKeyValues) EncodeBits(writer *bitbuf.Writer) error {
+	if err := writer.WriteUint32(uint32(len(msg.Data))); err != nil {
+		return err
+	}
+	return writer.WriteBytes(msg.Data)
+}
+
+// Skip reads past a CmdKeyValues in buf
+func (msg *CmdKeyValues) Skip(buf *bitbuf.Reader) error {
+	length, err := buf.ReadUint32()
+	if err != nil {
+		return err
+	}
+	return buf.SkipBytes(uint(length))
+}
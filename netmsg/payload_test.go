@@ -0,0 +1,110 @@
+package netmsg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+)
+
+// payloadMessages returns one of each handwritten message, with every optional field present
+func payloadMessages() []Message {
+	return []Message{
+		&SendTable{NeedsDecoder: true, Length: 21, Data: []byte{0xab, 0xcd, 0x1f}},
+		&ClassInfo{NumClasses: 3, Classes: []Class{
+			{ClassID: 0, ClassName: "CWorld", DataTableName: "DT_World"},
+			{ClassID: 1, ClassName: "CPlayer", DataTableName: "DT_Player"},
+			{ClassID: 2, ClassName: "CTeam", DataTableName: "DT_Team"},
+		}},
+		&ClassInfo{NumClasses: 250, CreateOnClient: true},
+		&CreateStringTable{IsFilenames: true, Name: "downloadables", MaxEntries: 8192, NumEntries: 2, Length: 12, Data: []byte{0x34, 0x02}},
+		&CreateStringTable{Name: "userinfo", MaxEntries: 256, NumEntries: 1, Length: 9, UserDataFixedSize: true, UserDataSize: 340, UserDataSizeBits: 12, DataCompressed: true, Data: []byte{0xff, 0x01}},
+		&UpdateStringTable{TableID: 7, ChangedEntries: 1, Length: 8, Data: []byte{0x12}},
+		&UpdateStringTable{TableID: 31, ChangedEntries: 600, Length: 3, Data: []byte{0x05}},
+		&VoiceInit{Codec: "vaudio_speex", Quality: 5},
+		&VoiceInit{Codec: "vaudio_celt", Quality: 255, SampleRate: 22050},
+		&VoiceData{FromClient: 4, Proximity: 1, Length: 16, Data: []byte{0x01, 0x02}},
+		&Sounds{Reliable: true, NumSounds: 1, Length: 5, Data: []byte{0x11}},
+		&Sounds{NumSounds: 9, Length: 300, Data: make([]byte, 38)},
+		&BSPDecal{Position: [3]float32{128, -64.5, 0}, DecalTextureIndex: 300, LowPriority: true},
+		&BSPDecal{Position: [3]float32{1, 2, 3}, DecalTextureIndex: 1, EntityIndex: 1000, ModelIndex: 8000},
+		&UserMessage{MsgType: 5, Length: 24, Data: []byte("hi\x00")},
+		&EntityMessage{EntityIndex: 2047, ClassID: 511, Length: 1, Data: []byte{0x01}},
+		&GameEvent{Length: 10, Data: []byte{0xff, 0x03}},
+		&PacketEntities{MaxEntries: 2047, DeltaFrom: -1, Baseline: true, UpdatedEntries: 12, Length: 7, UpdateBaseline: true, Data: []byte{0x7f}},
+		&PacketEntities{MaxEntries: 1000, IsDelta: true, DeltaFrom: 123456, UpdatedEntries: 1, Length: 0, Data: []byte{}},
+		&TempEntities{NumEntries: 2, Length: 17, Data: []byte{0x01, 0x02, 0x01}},
+		&Menu{MenuType: 2, Data: []byte("\x00menu\x00")},
+		&GameEventList{NumEvents: 400, Length: 4, Data: []byte{0x0f}},
+		&CmdKeyValues{Data: []byte{1, 2, 3, 4, 5}},
+	}
+}
+
+func TestPayloadMessages_RoundTrip(t *testing.T) {
+	for _, expected := range payloadMessages() {
+		writer := bitbuf.NewGrowableWriter(0, 0)
+		if err := expected.EncodeBits(writer); err != nil {
+			t.Fatalf("%s: %s", expected.Type(), err)
+		}
+
+		actual := reflect.New(reflect.TypeOf(expected).Elem()).Interface().(Message)
+		reader := bitbuf.NewReader(writer.Data())
+		if err := actual.DecodeBits(reader); err != nil {
+			t.Fatalf("%s: %s", expected.Type(), err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected: %+v, but received: %+v", expected, actual)
+		}
+		if reader.BitsRead() != writer.BitsWritten() {
+			t.Errorf("%s. expected: %d bits read, but received: %d", expected.Type(), writer.BitsWritten(), reader.BitsRead())
+		}
+	}
+}
+
+func TestPayloadMessages_Skip(t *testing.T) {
+	for _, msg := range payloadMessages() {
+		skipper, ok := msg.(Skipper)
+		if !ok {
+			continue
+		}
+		writer := bitbuf.NewGrowableWriter(0, 0)
+		if err := msg.EncodeBits(writer); err != nil {
+			t.Fatal(err)
+		}
+
+		reader := bitbuf.NewReader(writer.Data())
+		if err := skipper.Skip(reader); err != nil {
+			t.Fatalf("%s: %s", msg.Type(), err)
+		}
+		if reader.BitsRead() != writer.BitsWritten() {
+			t.Errorf("%s. expected: %d bits skipped, but received: %d", msg.Type(), writer.BitsWritten(), reader.BitsRead())
+		}
+	}
+}
+
+func TestPayloadMessages_LengthTooLarge(t *testing.T) {
+	for _, msg := range []Message{
+		&GameEvent{Length: 1 << LengthBits, Data: make([]byte, 1<<LengthBits/8)},
+		&UpdateStringTable{TableID: 1 << MaxTableBits, ChangedEntries: 1},
+		&Menu{Data: make([]byte, 1<<16)},
+	} {
+		if err := msg.EncodeBits(bitbuf.NewGrowableWriter(0, 0)); err == nil {
+			t.Errorf("%s. expected an error, but received none", msg.Type())
+		}
+	}
+}
+
+func TestClassInfo_EncodeBits_CountMismatch(t *testing.T) {
+	msg := &ClassInfo{NumClasses: 2, Classes: []Class{{ClassName: "CWorld"}}}
+	if err := msg.EncodeBits(bitbuf.NewGrowableWriter(0, 0)); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestLog2(t *testing.T) {
+	for value, expected := range map[uint32]uint{0: 0, 1: 0, 2: 1, 3: 1, 255: 7, 256: 8, 8192: 13} {
+		if actual := log2(value); actual != expected {
+			t.Errorf("log2(%d). expected: %d, but received: %d", value, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package netmsg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/galaco/bitbuf"
+)
+
+// ErrUnknownMessage is matched by errors.Is for an UnknownMessageError
+var ErrUnknownMessage = errors.New("unknown net message")
+
+// UnknownMessageError is returned when a message type has no registered message or unknown handler.
+// Messages carry no common length, so the rest of the buffer cannot be parsed.
+type UnknownMessageError struct {
+	Type   Type
+	BitPos uint
+}
+
+func (e *UnknownMessageError) Error() string {
+	return fmt.Sprintf("unknown net message %s at bit %d", e.Type, e.BitPos)
+}
+
+// Unwrap returns ErrUnknownMessage
+func (e *UnknownMessageError) Unwrap() error {
+	return ErrUnknownMessage
+}
+
+// Handler is called with every decoded message of the type it is registered for
+type Handler func(msg Message) error
+
+// UnknownHandler is called for a message type with nothing registered for it.
+// buf is positioned after the type, and the handler must read past the message body.
+type UnknownHandler func(msgType Type, buf *bitbuf.Reader) error
+
+// Registry decodes net messages by type, and dispatches them to handlers
+type Registry struct {
+	constructors map[Type]func() Message
+	handlers     map[Type][]Handler
+	skipped      map[Type]bool
+	unknown      UnknownHandler
+}
+
+// NewRegistry returns a Registry with every built in message registered
+func NewRegistry() *Registry {
+	registry := &Registry{
+		constructors: map[Type]func() Message{},
+		handlers:     map[Type][]Handler{},
+		skipped:      map[Type]bool{},
+	}
+	registry.Register(NetNOP, func() Message { return &NOP{} })
+	registry.Register(NetDisconnect, func() Message { return &Disconnect{} })
+	registry.Register(NetFile, func() Message { return &File{} })
+	registry.Register(NetTick, func() Message { return &Tick{} })
+	registry.Register(NetStringCmd, func() Message { return &StringCmd{} })
+	registry.Register(NetSetConVar, func() Message { return &SetConVar{} })
+	registry.Register(NetSignonState, func() Message { return &SignonState{} })
+	registry.Register(SvcPrint, func() Message { return &Print{} })
+	registry.Register(SvcServerInfo, func() Message { return &ServerInfo{} })
+	registry.Register(SvcSendTable, func() Message { return &SendTable{} })
+	registry.Register(SvcClassInfo, func() Message { return &ClassInfo{} })
+	registry.Register(SvcSetPause, func() Message { return &SetPause{} })
+	registry.Register(SvcCreateStringTable, func() Message { return &CreateStringTable{} })
+	registry.Register(SvcUpdateStringTable, func() Message { return &UpdateStringTable{} })
+	registry.Register(SvcVoiceInit, func() Message { return &VoiceInit{} })
+	registry.Register(SvcVoiceData, func() Message { return &VoiceData{} })
+	registry.Register(SvcSounds, func() Message { return &Sounds{} })
+	registry.Register(SvcSetView, func() Message { return &SetView{} })
+	registry.Register(SvcFixAngle, func() Message { return &FixAngle{} })
+	registry.Register(SvcCrosshairAngle, func() Message { return &CrosshairAngle{} })
+	registry.Register(SvcBSPDecal, func() Message { return &BSPDecal{} })
+	registry.Register(SvcUserMessage, func() Message { return &UserMessage{} })
+	registry.Register(SvcEntityMessage, func() Message { return &EntityMessage{} })
+	registry.Register(SvcGameEvent, func() Message { return &GameEvent{} })
+	registry.Register(SvcPacketEntities, func() Message { return &PacketEntities{} })
+	registry.Register(SvcTempEntities, func() Message { return &TempEntities{} })
+	registry.Register(SvcPrefetch, func() Message { return &Prefetch{} })
+	registry.Register(SvcMenu, func() Message { return &Menu{} })
+	registry.Register(SvcGameEventList, func() Message { return &GameEventList{} })
+	registry.Register(SvcGetCvarValue, func() Message { return &GetCvarValue{} })
+	registry.Register(SvcCmdKeyValues, func() Message { return &CmdKeyValues{} })
+	return registry
+}
+
+// Register sets the constructor for a message type, replacing any existing one.
+// This can be used to add game specific messages, or to override a built in message.
+func (registry *Registry) Register(msgType Type, constructor func() Message) {
+	registry.constructors[msgType] = constructor
+}
+
+// OnMessage adds a handler for a message type. Handlers are called in the order they were added.
+func (registry *Registry) OnMessage(msgType Type, handler Handler) {
+	registry.handlers[msgType] = append(registry.handlers[msgType], handler)
+}
+
+// OnUnknown sets the handler for message types with nothing registered for them.
+func (registry *Registry) OnUnknown(handler UnknownHandler) {
+	registry.unknown = handler
+}
+
+// Skip marks message types to be passed over by ReadMessage and Parse, without calling their handlers.
+// Messages that implement Skipper are passed over without being decoded.
+func (registry *Registry) Skip(msgTypes ...Type) {
+	for _, msgType := range msgTypes {
+		registry.skipped[msgType] = true
+	}
+}
+
+// ReadMessage reads the next message from buf, passing over skipped types.
+// Unknown types are passed to the unknown handler, then reading continues with the next message.
+// io.EOF is returned once there are too few bits left to hold another message type,
+// so buf is expected to hold a whole packet, rather than be a stream.
+// As in the engine, zeroed padding at the end of a packet reads as NOP messages.
+func (registry *Registry) ReadMessage(buf *bitbuf.Reader) (Message, error) {
+	for {
+		bitPos := buf.BitsRead()
+		if buf.Size()-bitPos < TypeBits {
+			return nil, io.EOF
+		}
+		rawType, err := buf.ReadUint32Bits(TypeBits)
+		if err != nil {
+			return nil, err
+		}
+		msgType := Type(rawType)
+
+		constructor, ok := registry.constructors[msgType]
+		if !ok {
+			if registry.unknown == nil {
+				return nil, &UnknownMessageError{Type: msgType, BitPos: bitPos}
+			}
+			if err := registry.unknown(msgType, buf); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		msg := constructor()
+		if registry.skipped[msgType] {
+			if skipper, ok := msg.(Skipper); ok {
+				err = skipper.Skip(buf)
+			} else {
+				err = msg.DecodeBits(buf)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("skipping %s: %w", msgType, err)
+			}
+			continue
+		}
+		if err := msg.DecodeBits(buf); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", msgType, err)
+		}
+		return msg, nil
+	}
+}
+
+// Parse reads every message in buf, calling the handlers registered for each.
+func (registry *Registry) Parse(buf *bitbuf.Reader) error {
+	for {
+		msg, err := registry.ReadMessage(buf)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, handler := range registry.handlers[msg.Type()] {
+			if err := handler(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package netmsg
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+)
+
+// writePacket writes msgs to a packet, padded to a whole byte
+func writePacket(t *testing.T, msgs ...Message) []byte {
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	for _, msg := range msgs {
+		if err := WriteMessage(writer, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return writer.Data()
+}
+
+func TestRegistry_ReadMessage(t *testing.T) {
+	expected := []Message{
+		&Tick{Tick: 4500, HostFrameTime: 1500, HostFrameTimeStdDev: 20},
+		&SetConVar{ConVars: []ConVar{{Name: "sv_cheats", Value: "0"}, {Name: "mp_timelimit", Value: "30"}}},
+		&ServerInfo{Protocol: 24, ServerCount: 3, IsDedicated: true, MaxClasses: 280, MapName: "de_dust2", TickInterval: 0.015625},
+	}
+	expected = append(expected, payloadMessages()...)
+	packet := writePacket(t, expected...)
+
+	sut := NewRegistry()
+	reader := bitbuf.NewReader(packet)
+	for _, msg := range expected {
+		actual, err := sut.ReadMessage(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, msg) {
+			t.Errorf("expected: %+v, but received: %+v", msg, actual)
+		}
+	}
+	// Zeroed padding at the end of the packet reads as NOPs
+	for {
+		msg, err := sut.ReadMessage(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := msg.(*NOP); !ok {
+			t.Errorf("expected: %+v, but received: %+v", &NOP{}, msg)
+		}
+	}
+}
+
+func TestRegistry_Parse(t *testing.T) {
+	packet := writePacket(t,
+		&Tick{Tick: 1},
+		&Print{Text: "hello"},
+		&Tick{Tick: 2},
+		&GameEvent{Length: 8, Data: []byte{0x2a}},
+	)
+
+	sut := NewRegistry()
+	var ticks []int32
+	sut.OnMessage(NetTick, func(msg Message) error {
+		ticks = append(ticks, msg.(*Tick).Tick)
+		return nil
+	})
+	var events int
+	sut.OnMessage(SvcGameEvent, func(msg Message) error {
+		events++
+		return nil
+	})
+	if err := sut.Parse(bitbuf.NewReader(packet)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ticks, []int32{1, 2}) {
+		t.Errorf("expected: %v, but received: %v", []int32{1, 2}, ticks)
+	}
+	if events != 1 {
+		t.Errorf("expected: %d, but received: %d", 1, events)
+	}
+}
+
+func TestRegistry_Parse_HandlerError(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	sut := NewRegistry()
+	sut.OnMessage(NetTick, func(msg Message) error {
+		return handlerErr
+	})
+	if err := sut.Parse(bitbuf.NewReader(writePacket(t, &Tick{}))); err != handlerErr {
+		t.Errorf("expected: %v, but received: %v", handlerErr, err)
+	}
+}
+
+func TestRegistry_Skip(t *testing.T) {
+	packet := writePacket(t,
+		&PacketEntities{MaxEntries: 10, DeltaFrom: -1, Length: 20, Data: []byte{1, 2, 3}},
+		&Print{Text: "skipped too"},
+		&Tick{Tick: 7},
+	)
+
+	sut := NewRegistry()
+	sut.Skip(SvcPacketEntities, SvcPrint)
+	sut.OnMessage(SvcPacketEntities, func(msg Message) error {
+		t.Error("expected skipped messages not to be handled")
+		return nil
+	})
+	msg, err := sut.ReadMessage(bitbuf.NewReader(packet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tick, ok := msg.(*Tick); !ok || tick.Tick != 7 {
+		t.Errorf("expected: %+v, but received: %+v", &Tick{Tick: 7}, msg)
+	}
+}
+
+func TestRegistry_Unknown(t *testing.T) {
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	writer.WriteUnsignedBitInt32(40, TypeBits)
+	writer.WriteUint16(0xbeef)
+	WriteMessage(writer, &Tick{Tick: 9})
+
+	sut := NewRegistry()
+	_, err := sut.ReadMessage(bitbuf.NewReader(writer.Data()))
+	if !errors.Is(err, ErrUnknownMessage) {
+		t.Errorf("expected: %v, but received: %v", ErrUnknownMessage, err)
+	}
+	var unknownErr *UnknownMessageError
+	if !errors.As(err, &unknownErr) || unknownErr.Type != 40 || unknownErr.BitPos != 0 {
+		t.Errorf("expected: type 40 at bit 0, but received: %v", err)
+	}
+
+	var unknownValue uint16
+	sut.OnUnknown(func(msgType Type, buf *bitbuf.Reader) (err error) {
+		if msgType != 40 {
+			t.Errorf("expected: %d, but received: %d", 40, msgType)
+		}
+		unknownValue, err = buf.ReadUint16()
+		return err
+	})
+	msg, err := sut.ReadMessage(bitbuf.NewReader(writer.Data()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknownValue != 0xbeef {
+		t.Errorf("expected: %d, but received: %d", 0xbeef, unknownValue)
+	}
+	if tick, ok := msg.(*Tick); !ok || tick.Tick != 9 {
+		t.Errorf("expected: %+v, but received: %+v", &Tick{Tick: 9}, msg)
+	}
+}
+
+// customMessage is a game specific message, used to test registration
+type customMessage struct {
+	Value uint8
+}
+
+func (msg *customMessage) Type() Type { return 40 }
+
+func (msg *customMessage) DecodeBits(buf *bitbuf.Reader) (err error) {
+	msg.Value, err = buf.ReadUint8()
+	return err
+}
+
+func (msg *customMessage) EncodeBits(writer *bitbuf.Writer) error {
+	return writer.WriteUint8(msg.Value)
+}
+
+func TestRegistry_Register(t *testing.T) {
+	sut := NewRegistry()
+	sut.Register(40, func() Message { return &customMessage{} })
+
+	msg, err := sut.ReadMessage(bitbuf.NewReader(writePacket(t, &customMessage{Value: 77})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(msg, &customMessage{Value: 77}) {
+		t.Errorf("expected: %+v, but received: %+v", &customMessage{Value: 77}, msg)
+	}
+}
+
+func TestRegistry_ReadMessage_Truncated(t *testing.T) {
+	packet := writePacket(t, &GameEvent{Length: 64, Data: make([]byte, 8)})
+	_, err := NewRegistry().ReadMessage(bitbuf.NewReader(packet[:4]))
+	if err == nil || err == io.EOF {
+		t.Errorf("expected: a decode error, but received: %v", err)
+	}
+}
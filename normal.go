@@ -0,0 +1,136 @@
+package bitbuf
+
+import "math"
+
+// Source engine normal encoding constants.
+// These must match the engine exactly for encoded values to be bit-compatible.
+const (
+	// NormalFractionalBits is the number of bits used for a normal component
+	NormalFractionalBits = 11
+	// NormalDenominator is the fractional denominator of a normal component
+	NormalDenominator = (1 << NormalFractionalBits) - 1
+	// NormalResolution is the smallest representable normal component
+	NormalResolution = 1.0 / NormalDenominator
+)
+
+// ReadBitNormal reads a single component of a normal, in the range [-1..1]
+func (buf *Reader) ReadBitNormal() (float32, error) {
+	negative, err := buf.ReadOneBit()
+	if err != nil {
+		return 0, err
+	}
+	fractVal, err := buf.ReadUint32Bits(NormalFractionalBits)
+	if err != nil {
+		return 0, err
+	}
+
+	// The engine computes this at double precision before narrowing
+	value := float32(float64(fractVal) * NormalResolution)
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// ReadBitVec3Normal reads a unit length normal.
+// Only the x and y components are sent; z is derived from them and a sign bit.
+func (buf *Reader) ReadBitVec3Normal() (vec [3]float32, err error) {
+	hasX, err := buf.ReadOneBit()
+	if err != nil {
+		return vec, err
+	}
+	hasY, err := buf.ReadOneBit()
+	if err != nil {
+		return vec, err
+	}
+	if hasX {
+		if vec[0], err = buf.ReadBitNormal(); err != nil {
+			return vec, err
+		}
+	}
+	if hasY {
+		if vec[1], err = buf.ReadBitNormal(); err != nil {
+			return vec, err
+		}
+	}
+	negativeZ, err := buf.ReadOneBit()
+	if err != nil {
+		return vec, err
+	}
+
+	if xySquared := vec[0]*vec[0] + vec[1]*vec[1]; xySquared < 1 {
+		vec[2] = float32(math.Sqrt(float64(1 - xySquared)))
+	}
+	if negativeZ {
+		vec[2] = -vec[2]
+	}
+	return vec, nil
+}
+
+// ReadBitAngle reads an angle in degrees, quantised to numBits
+func (buf *Reader) ReadBitAngle(numBits uint) (float32, error) {
+	value, err := buf.ReadUint32Bits(numBits)
+	if err != nil {
+		return 0, err
+	}
+	return float32(float64(value) * (360.0 / float64(uint64(1)<<numBits))), nil
+}
+
+// ReadBitAngles reads a set of pitch, yaw & roll angles.
+// They are encoded in the same way as a coord vector.
+func (buf *Reader) ReadBitAngles() ([3]float32, error) {
+	return buf.ReadBitVec3Coord()
+}
+
+// WriteBitNormal writes a single component of a normal, in the range [-1..1]
+func (writer *Writer) WriteBitNormal(value float32) error {
+	negative := float64(value) <= -NormalResolution
+	// +/-1 are valid normal components, and are encoded as all ones
+	fractVal := uint32(absInt32(int32(value * NormalDenominator)))
+	if fractVal > NormalDenominator {
+		fractVal = NormalDenominator
+	}
+
+	if err := writer.WriteOneBit(negative); err != nil {
+		return err
+	}
+	return writer.WriteUnsignedBitInt32(fractVal, NormalFractionalBits)
+}
+
+// WriteBitVec3Normal writes a unit length normal.
+// Only the x and y components are sent, along with the sign of z.
+func (writer *Writer) WriteBitVec3Normal(vec [3]float32) error {
+	hasX := float64(vec[0]) >= NormalResolution || float64(vec[0]) <= -NormalResolution
+	hasY := float64(vec[1]) >= NormalResolution || float64(vec[1]) <= -NormalResolution
+
+	if err := writer.WriteOneBit(hasX); err != nil {
+		return err
+	}
+	if err := writer.WriteOneBit(hasY); err != nil {
+		return err
+	}
+	if hasX {
+		if err := writer.WriteBitNormal(vec[0]); err != nil {
+			return err
+		}
+	}
+	if hasY {
+		if err := writer.WriteBitNormal(vec[1]); err != nil {
+			return err
+		}
+	}
+	return writer.WriteOneBit(float64(vec[2]) <= -NormalResolution)
+}
+
+// WriteBitAngle writes an angle in degrees, quantised to numBits
+func (writer *Writer) WriteBitAngle(value float32, numBits uint) error {
+	shift := uint64(1) << numBits
+	quantised := int64((float64(value) / 360.0) * float64(shift))
+	return writer.WriteUnsignedBitInt32(uint32(uint64(quantised)&(shift-1)), numBits)
+}
+
+// WriteBitAngles writes a set of pitch, yaw & roll angles.
+// They are encoded in the same way as a coord vector.
+func (writer *Writer) WriteBitAngles(angles [3]float32) error {
+	return writer.WriteBitVec3Coord(angles)
+}
@@ -0,0 +1,106 @@
+package bitbuf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWriter_WriteBitNormal(t *testing.T) {
+	sut := NewWriter(8)
+
+	if err := sut.WriteBitNormal(-1); err != nil {
+		t.Error(err)
+	}
+
+	// sign bit, followed by an all ones fraction
+	expected := uint32(NormalDenominator<<1 | 1)
+	if val, err := NewReader(sut.Data()).ReadUint32Bits(12); err != nil || val != expected {
+		t.Errorf("expected: %b, but received: %b (%v)", expected, val, err)
+	}
+}
+
+func TestReader_ReadBitNormal(t *testing.T) {
+	values := []float32{0, 1, -1, 0.5, -0.25, 0.70710678, 2}
+	for _, v := range values {
+		sut := NewWriter(8)
+		if err := sut.WriteBitNormal(v); err != nil {
+			t.Error(err)
+		}
+
+		val, err := NewReader(sut.Data()).ReadBitNormal()
+		if err != nil {
+			t.Error(err)
+		}
+		if math.Abs(float64(val)-math.Max(-1, math.Min(1, float64(v)))) > NormalResolution {
+			t.Errorf("expected: %f, but received: %f", v, val)
+		}
+
+		// Re-encoding a decoded value must be lossless
+		again := NewWriter(8)
+		if err := again.WriteBitNormal(val); err != nil {
+			t.Error(err)
+		}
+		if roundTrip, _ := NewReader(again.Data()).ReadBitNormal(); roundTrip != val {
+			t.Errorf("expected: %f, but received: %f", val, roundTrip)
+		}
+	}
+}
+
+func TestReader_ReadBitVec3Normal(t *testing.T) {
+	values := [][3]float32{
+		{0, 0, 1},
+		{0, 0, -1},
+		{0.6, -0.8, 0},
+		{0.26726124, 0.53452248, -0.80178373},
+	}
+	for _, v := range values {
+		sut := NewWriter(8)
+		if err := sut.WriteBitVec3Normal(v); err != nil {
+			t.Error(err)
+		}
+
+		val, err := NewReader(sut.Data()).ReadBitVec3Normal()
+		if err != nil {
+			t.Error(err)
+		}
+		for i := range v {
+			if math.Abs(float64(val[i]-v[i])) > 0.03 {
+				t.Errorf("expected: %v, but received: %v", v, val)
+			}
+		}
+	}
+}
+
+func TestReader_ReadBitAngle(t *testing.T) {
+	testCases := []struct {
+		numBits  uint
+		value    float32
+		expected float32
+	}{
+		{8, 90, 90},
+		{8, 359, 358.59375},
+		{16, -90, 270},
+		{16, 12.3456, 12.3431396484375},
+	}
+	for _, tc := range testCases {
+		sut := NewWriter(8)
+		if err := sut.WriteBitAngle(tc.value, tc.numBits); err != nil {
+			t.Error(err)
+		}
+		if val, err := NewReader(sut.Data()).ReadBitAngle(tc.numBits); err != nil || val != tc.expected {
+			t.Errorf("expected: %f, but received: %f (%v)", tc.expected, val, err)
+		}
+	}
+}
+
+func TestReader_ReadBitAngles(t *testing.T) {
+	expected := [3]float32{-45.5, 180.25, 0}
+	sut := NewWriter(16)
+	if err := sut.WriteBitAngles(expected); err != nil {
+		t.Error(err)
+	}
+
+	if val, err := NewReader(sut.Data()).ReadBitAngles(); err != nil || val != expected {
+		t.Errorf("expected: %v, but received: %v (%v)", expected, val, err)
+	}
+}
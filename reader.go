@@ -1,31 +1,120 @@
 package bitbuf
 
 import (
-	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 )
 
+const (
+	// streamChunkSize is the minimum number of bytes a stream Reader will
+	// request from its source each time its window runs dry
+	streamChunkSize = 4096
+	// maxConsecutiveEmptyReads is how many times a source may return no data
+	// and no error before a stream Reader gives up on it
+	maxConsecutiveEmptyReads = 100
+)
+
 type Reader struct {
-	internalBuffer bytes.Buffer
+	internalBuffer []byte
 	totalBits      uint
 	currentBit     uint
+
+	// source is only set for readers created with NewStreamReader.
+	// internalBuffer then holds a window of the stream that begins
+	// bufferOffset bytes into it.
+	source       io.Reader
+	sourceErr    error
+	bufferOffset uint
+
+	// sticky readers latch the first bounds failure in err, and refuse
+	// all further reads until Reset.
+	sticky bool
+	err    error
+
+	// window caches the next windowBits unread bits of the buffer, so that
+	// consecutive reads don't reload them. It is only valid while windowPos
+	// matches currentBit, so moving the cursor implicitly invalidates it.
+	window     uint64
+	windowBits uint
+	windowPos  uint
 }
 
 // Size returns size (in bits, NOT bytes)
+// For a stream Reader this is the number of bits received from the source so far.
 func (buf *Reader) Size() uint {
 	return buf.totalBits
 }
 
 // Seek seek to a specific Bit. Not Byte!
-func (buf *Reader) Seek(offset int) {
-	buf.currentBit = uint(offset)
+// Seeking beyond the end of the buffer returns a *BoundsError, and leaves the cursor in place.
+func (buf *Reader) Seek(offset int) error {
+	_, err := buf.SeekBits(int64(offset), io.SeekStart)
+	return err
+}
+
+// SeekBits sets the cursor to offset bits, interpreted according to whence in the
+// same way as io.Seeker: relative to the start, the cursor, or the end of the buffer.
+// It returns the new position in bits. A stream Reader cannot seek relative to the
+// end, or behind the window of data it holds.
+func (buf *Reader) SeekBits(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		if offset > math.MaxInt64-int64(buf.currentBit) {
+			return int64(buf.currentBit), buf.unrepresentable("seek")
+		}
+		target = int64(buf.currentBit) + offset
+	case io.SeekEnd:
+		if buf.source != nil {
+			return int64(buf.currentBit), errors.New("bitbuf cannot seek relative to the end of a stream")
+		}
+		if offset > math.MaxInt64-int64(buf.totalBits) {
+			return int64(buf.currentBit), buf.unrepresentable("seek")
+		}
+		target = int64(buf.totalBits) + offset
+	default:
+		return int64(buf.currentBit), fmt.Errorf("bitbuf invalid whence %d", whence)
+	}
+
+	if target < 0 {
+		return int64(buf.currentBit), fmt.Errorf("%w: cannot seek to negative bit %d", ErrOutOfBounds, target)
+	}
+	if uint(target) < buf.currentBit {
+		if uint(target) < buf.bufferOffset<<3 {
+			return int64(buf.currentBit), &StreamWindowError{Op: "seek", Position: uint(target), WindowStart: buf.bufferOffset << 3}
+		}
+	} else if err := buf.ensureAvailable("seek", uint(target)-buf.currentBit); err != nil {
+		return int64(buf.currentBit), err
+	}
+	buf.currentBit = uint(target)
+	return target, nil
+}
+
+// SkipBits advances the cursor by numBits.
+// Skipping beyond the end of the buffer returns a *BoundsError, and leaves the cursor in place.
+func (buf *Reader) SkipBits(numBits uint) error {
+	// checked here, as numBits may not be representable as an offset
+	if err := buf.ensureAvailable("seek", numBits); err != nil {
+		return err
+	}
+	_, err := buf.SeekBits(int64(numBits), io.SeekCurrent)
+	return err
+}
+
+// SkipBytes advances the cursor by numBytes.
+func (buf *Reader) SkipBytes(numBytes uint) error {
+	return buf.SkipBits(numBytes << 3)
 }
 
 // Data returns the entire buffer as []byte
+// For a stream Reader only the currently buffered window is returned.
 func (buf *Reader) Data() []byte {
-	return buf.internalBuffer.Bytes()
+	return buf.internalBuffer
 }
 
 // BitsRead returns number of bits read
@@ -34,8 +123,50 @@ func (buf *Reader) BitsRead() uint {
 }
 
 // Reset seeks back to start (0)
+// Any error latched in sticky mode is cleared.
 func (buf *Reader) Reset() {
 	buf.currentBit = 0
+	buf.err = nil
+}
+
+// SetStickyErrors enables or disables sticky error mode.
+// In sticky mode the first out of bounds read is latched, and every subsequent
+// read returns a zero value and that same error. This allows a sequence of reads
+// to be checked once at the end via Err or IsOverflowed.
+func (buf *Reader) SetStickyErrors(sticky bool) {
+	buf.sticky = sticky
+}
+
+// Err returns the error latched in sticky mode, if any.
+func (buf *Reader) Err() error {
+	return buf.err
+}
+
+// IsOverflowed returns whether a read beyond the end of the buffer has been latched in sticky mode.
+func (buf *Reader) IsOverflowed() bool {
+	return errors.Is(buf.err, ErrOutOfBounds)
+}
+
+// CheckAvailable returns a *BoundsError if fewer than numBits bits follow the cursor, without consuming any.
+// A stream Reader reads ahead from its source as needed. This allows a length read from the data to be
+// checked before it is used to allocate.
+func (buf *Reader) CheckAvailable(numBits uint) error {
+	return buf.ensureInBounds(numBits)
+}
+
+// IsByteAligned returns whether the cursor is on a byte boundary
+func (buf *Reader) IsByteAligned() bool {
+	return buf.currentBit&7 == 0
+}
+
+// BitsToNextByte returns the number of bits between the cursor and the next byte boundary
+func (buf *Reader) BitsToNextByte() uint {
+	return -buf.currentBit & 7
+}
+
+// AlignToByte advances the cursor to the next byte boundary, if it is not already on one
+func (buf *Reader) AlignToByte() error {
+	return buf.SkipBits(buf.BitsToNextByte())
 }
 
 // ReadUint8 reads Uint8
@@ -100,61 +231,78 @@ func (buf *Reader) ReadUint32() (uint32, error) {
 
 // ReadInt64 reads Int64
 func (buf *Reader) ReadInt64() (int64, error) {
-	v, err := buf.ReadBytes(8)
+	v, err := buf.readInternal(64)
 	if err != nil {
 		return 0, err
 	}
-	return bytesToInt64(v)
+	return int64(v), err
 }
 
 // ReadUint64 reads Uint64
 func (buf *Reader) ReadUint64() (uint64, error) {
-	v, err := buf.ReadBytes(8)
+	v, err := buf.readInternal(64)
 	if err != nil {
 		return 0, err
 	}
-	val, err := bytesToInt64(v)
-	return uint64(val), err
+	return v, err
 }
 
 // ReadFloat32 reads a float32
 func (buf *Reader) ReadFloat32() (float32, error) {
-	v, err := buf.ReadBytes(4)
+	v, err := buf.readInternal(32)
 	if err != nil {
 		return 0, err
 	}
-	return bytesToFloat32(v)
+	return math.Float32frombits(uint32(v)), nil
 }
 
 // ReadFloat64 reads a float64
 func (buf *Reader) ReadFloat64() (float64, error) {
-	v, err := buf.ReadBytes(8)
+	v, err := buf.readInternal(64)
 	if err != nil {
 		return 0, err
 	}
-	return bytesToFloat64(v)
+	return math.Float64frombits(v), nil
 }
 
 // ReadBytes reads X number of consecutive bytes
 func (buf *Reader) ReadBytes(numBytes uint) ([]byte, error) {
-	if err := buf.ensureInBounds(numBytes << 3); err != nil {
-		return nil, err
+	if numBytes > math.MaxUint>>3 {
+		return nil, buf.unrepresentable("read")
 	}
 	return buf.ReadBits(numBytes << 3)
 }
 
+// ReadBytesInto fills dst with consecutive bytes, without allocating.
+func (buf *Reader) ReadBytesInto(dst []byte) error {
+	if uint(len(dst)) > math.MaxUint>>3 {
+		return buf.unrepresentable("read")
+	}
+	return buf.ReadBitsInto(dst, uint(len(dst))<<3)
+}
+
 // ReadString reads in string data of X length. Underlying implementation same as byte
 // Will stop on reaching null terminator.
 // If maxLength != 0 will read until null-terminator, EOF OR maxLength read reached.
 func (buf *Reader) ReadString(maxLength uint) (string, error) {
 	// Disregard oob for strings, as we can read until end or null termination
-	if maxLength == 0 {
-		maxLength = (buf.totalBits - buf.currentBit) / 8
+	unbounded := maxLength == 0
+	if unbounded {
+		if buf.source != nil {
+			// The end of a stream is unknown until we reach it
+			maxLength = math.MaxUint32
+		} else {
+			maxLength = (buf.totalBits - buf.currentBit) / 8
+		}
 	}
 
 	retVal := make([]byte, 0)
 	for i := uint(0); i < maxLength; i++ {
 		val, err := buf.ReadByte()
+		// As for a slice Reader, the end of a stream only ends a string of unbounded length
+		if unbounded && errors.Is(err, io.EOF) {
+			return string(retVal), nil
+		}
 		if val == 0 {
 			return string(retVal), err
 		}
@@ -163,34 +311,89 @@ func (buf *Reader) ReadString(maxLength uint) (string, error) {
 	return string(retVal), nil
 }
 
+// ReadStringInto reads string data into dst, without allocating, and returns the
+// number of bytes read, excluding any null terminator.
+// Reading stops on a null terminator, EOF, or once maxLength bytes have been read.
+// A maxLength of 0 means len(dst). dst must be able to hold maxLength bytes,
+// otherwise io.ErrShortBuffer is returned and nothing is read.
+func (buf *Reader) ReadStringInto(dst []byte, maxLength uint) (int, error) {
+	if maxLength == 0 {
+		maxLength = uint(len(dst))
+	}
+	if maxLength > uint(len(dst)) {
+		return 0, io.ErrShortBuffer
+	}
+	// Disregard oob for strings, as we can read until end or null termination
+	if remaining := (buf.totalBits - buf.currentBit) / 8; buf.source == nil && remaining < maxLength {
+		maxLength = remaining
+	}
+
+	for i := uint(0); i < maxLength; i++ {
+		val, err := buf.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return int(i), nil
+		}
+		if val == 0 {
+			return int(i), err
+		}
+		dst[i] = val
+	}
+	return int(maxLength), nil
+}
+
 // ReadBits reads a specific number of bits.
 func (buf *Reader) ReadBits(numBits uint) ([]byte, error) {
-	retVal := make([]byte, int(math.Ceil(float64(numBits)/8)))
+	// numBits is often a length read from the data, so must be checked before allocating
+	if err := buf.ensureInBounds(numBits); err != nil {
+		return nil, err
+	}
+	retVal := make([]byte, (numBits+7)>>3)
+	if err := buf.ReadBitsInto(retVal, numBits); err != nil {
+		return nil, err
+	}
+	return retVal, nil
+}
 
-	//unsigned char *pOut = (unsigned char*)pOutData;
+// ReadBitsInto reads a specific number of bits into dst, without allocating.
+// Any unused high bits of the final byte are set to 0.
+// dst must be at least (numBits+7)/8 bytes long, otherwise io.ErrShortBuffer is returned.
+func (buf *Reader) ReadBitsInto(dst []byte, numBits uint) error {
+	if uint(len(dst)) < (numBits+7)>>3 {
+		return io.ErrShortBuffer
+	}
+	if err := buf.ensureInBounds(numBits); err != nil {
+		return err
+	}
 	nBitsLeft := numBits
-
-	// align output to dword boundary
 	idx := 0
 
-	// read dwords
-	idx = 0
-	for nBitsLeft >= 32 {
-		retVal[idx], _ = buf.ReadByte()
-		idx++
-		retVal[idx], _ = buf.ReadByte()
-		idx++
-		retVal[idx], _ = buf.ReadByte()
-		idx++
-		retVal[idx], _ = buf.ReadByte()
-		idx++
+	// whole bytes can be copied directly when the cursor is aligned
+	if buf.IsByteAligned() {
+		first := buf.currentBit>>3 - buf.bufferOffset
+		idx = copy(dst[:numBits>>3], buf.internalBuffer[first:])
+		buf.currentBit += uint(idx) << 3
+		nBitsLeft -= uint(idx) << 3
+	}
 
-		nBitsLeft -= 32
+	// read qwords
+	for nBitsLeft >= 64 {
+		v, err := buf.readInternal(64)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint64(dst[idx:], v)
+		idx += 8
+
+		nBitsLeft -= 64
 	}
 
 	// read remaining bytes
 	for nBitsLeft >= 8 {
-		retVal[idx], _ = buf.ReadByte()
+		v, err := buf.readInternal(8)
+		if err != nil {
+			return err
+		}
+		dst[idx] = byte(v)
 		idx++
 
 		nBitsLeft -= 8
@@ -198,74 +401,280 @@ func (buf *Reader) ReadBits(numBits uint) ([]byte, error) {
 
 	// read remaining bits
 	if nBitsLeft > 0 {
-		v, _ := buf.readInternal(nBitsLeft)
-		retVal[idx] = byte(v)
+		v, err := buf.readInternal(nBitsLeft)
+		if err != nil {
+			return err
+		}
+		dst[idx] = byte(v)
 	}
 
-	return retVal, nil
+	return nil
 }
 
 // ReadUint32Bits reads a specific number of bits that will be treated as a Uint32
 func (buf *Reader) ReadUint32Bits(numBits uint) (uint32, error) {
-	return buf.readInternal(numBits)
+	if numBits > 32 {
+		return 0, fmt.Errorf("%w: cannot handle more than 32 bits in a Uint32 read", ErrWidthTooLarge)
+	}
+	v, err := buf.readInternal(numBits)
+	return uint32(v), err
 }
 
-// ReadInt32Bits reads a specific number of bits that will be treated as an Int32
+// ReadInt32Bits reads a specific number of bits that will be treated as an Int32.
+// The value is sign-extended from the highest bit read.
 func (buf *Reader) ReadInt32Bits(numBits uint) (int32, error) {
+	if numBits > 32 {
+		return 0, fmt.Errorf("%w: cannot handle more than 32 bits in an Int32 read", ErrWidthTooLarge)
+	}
 	v, err := buf.readInternal(numBits)
-	return int32(v), err
+	if err != nil || numBits == 0 {
+		return 0, err
+	}
+	return int32(uint32(v)<<(32-numBits)) >> (32 - numBits), nil
+}
+
+// ReadUBitLong reads a specific number of bits as an unsigned value.
+// It is equivalent to ReadUint32Bits, and named to match the Source engine.
+func (buf *Reader) ReadUBitLong(numBits uint) (uint32, error) {
+	return buf.ReadUint32Bits(numBits)
+}
+
+// ReadSBitLong reads a specific number of bits as a sign-extended value.
+// It is equivalent to ReadInt32Bits, and named to match the Source engine.
+func (buf *Reader) ReadSBitLong(numBits uint) (int32, error) {
+	return buf.ReadInt32Bits(numBits)
+}
+
+// ReadUint64Bits reads a specific number of bits that will be treated as a Uint64
+func (buf *Reader) ReadUint64Bits(numBits uint) (uint64, error) {
+	return buf.readInternal(numBits)
+}
+
+// ReadInt64Bits reads a specific number of bits that will be treated as an Int64.
+// The value is sign-extended from the highest bit read.
+func (buf *Reader) ReadInt64Bits(numBits uint) (int64, error) {
+	v, err := buf.readInternal(numBits)
+	if err != nil || numBits == 0 {
+		return 0, err
+	}
+	return int64(v<<(64-numBits)) >> (64 - numBits), nil
 }
 
 // ReadOneBit reads a single bit as a boolean
-func (buf *Reader) ReadOneBit() bool {
-	value := uint8(buf.internalBuffer.Bytes()[buf.currentBit>>3] >> (buf.currentBit & 7))
-	buf.currentBit++
-	return (value & 1) != 0
+func (buf *Reader) ReadOneBit() (bool, error) {
+	value, err := buf.readInternal(1)
+	return value != 0, err
 }
 
-func (buf *Reader) readInternal(numBits uint) (uint32, error) {
+// PeekUint32Bits returns the next numBits bits as a Uint32, without advancing the cursor
+func (buf *Reader) PeekUint32Bits(numBits uint) (uint32, error) {
+	defer buf.restore(buf.currentBit, buf.err)
+	return buf.ReadUint32Bits(numBits)
+}
+
+// PeekOneBit returns the next bit as a boolean, without advancing the cursor
+func (buf *Reader) PeekOneBit() (bool, error) {
+	defer buf.restore(buf.currentBit, buf.err)
+	return buf.ReadOneBit()
+}
+
+// PeekBytes returns the next numBytes bytes, without advancing the cursor
+func (buf *Reader) PeekBytes(numBytes uint) ([]byte, error) {
+	defer buf.restore(buf.currentBit, buf.err)
+	return buf.ReadBytes(numBytes)
+}
+
+// PeekBits returns the next numBits bits, without advancing the cursor
+func (buf *Reader) PeekBits(numBits uint) ([]byte, error) {
+	defer buf.restore(buf.currentBit, buf.err)
+	return buf.ReadBits(numBits)
+}
+
+// restore returns the cursor to a position saved before a peek, along with the
+// sticky error, so that a peek which fails doesn't prevent later reads
+func (buf *Reader) restore(position uint, err error) {
+	buf.currentBit = position
+	buf.err = err
+}
+
+func (buf *Reader) readInternal(numBits uint) (uint64, error) {
 	if numBits > 64 {
-		return 0, errors.New("cannot handle more than 64 bits in a single read")
+		return 0, fmt.Errorf("%w: cannot handle more than 64 bits in a single read", ErrWidthTooLarge)
 	}
-	err := buf.ensureInBounds(numBits)
-	if err != nil {
-		return 0, err
+	// Only plain in bounds reads avoid the full check
+	if buf.err != nil || buf.source != nil || buf.currentBit+numBits > buf.totalBits {
+		if err := buf.ensureInBounds(numBits); err != nil {
+			return 0, err
+		}
 	}
 
-	firstByte := buf.currentBit / 8
-	startBit := (buf.currentBit & 31) % 8
-	//lastBit := buf.currentBit + numBits - 1
-	//wordOffset1 := uint(buf.currentBit >> 5)
-	//wordOffset2 := uint(lastBit >> 5) + 4
-	buf.currentBit += numBits
+	if buf.windowPos != buf.currentBit || buf.windowBits < numBits {
+		buf.loadWindow()
+	}
 
-	bitmask := uint32(2<<(uint(numBits)-1)) - 1
+	value := buf.window
+	if numBits > buf.windowBits {
+		// 64 bits starting part way through a byte will straddle a 9th byte
+		value |= buf.loadQWord(buf.currentBit>>3-buf.bufferOffset+8) << buf.windowBits
+		buf.windowBits = 0
+	} else {
+		buf.window >>= numBits
+		buf.windowBits -= numBits
+	}
+	buf.currentBit += numBits
+	buf.windowPos = buf.currentBit
 
-	//dw1 := LoadLittleDWord( (unsigned long* RESTRICT)m_pData, wordOffset1) >> startBit
-	//dw2 := LoadLittleDWord( (unsigned long* RESTRICT)m_pData, wordOffset2) << (32 - startBit)
-	dw1, _ := bytesToUint32(buf.internalBuffer.Bytes()[firstByte : firstByte+4])
-	dw1 = dw1 >> startBit
-	dw2 := uint32(0)
-	if buf.totalBits-buf.currentBit >= 64 {
-		dw2, _ = bytesToUint32(buf.internalBuffer.Bytes()[firstByte+4 : firstByte+8])
-		dw2 = dw2 << (32 - startBit)
+	if numBits < 64 {
+		value &= (uint64(1) << numBits) - 1
 	}
+	return value, nil
+}
 
-	return (dw1 | dw2) & bitmask, nil
+// loadWindow caches the qword containing the cursor, discarding any bits before it
+func (buf *Reader) loadWindow() {
+	startBit := buf.currentBit & 7
+	buf.window = buf.loadQWord(buf.currentBit>>3-buf.bufferOffset) >> startBit
+	buf.windowBits = 64 - startBit
+	buf.windowPos = buf.currentBit
+}
+
+// loadQWord reads a little-endian qword from the buffer. Bytes beyond the
+// end of the buffer are treated as 0.
+func (buf *Reader) loadQWord(index uint) uint64 {
+	if index+8 <= uint(len(buf.internalBuffer)) {
+		return binary.LittleEndian.Uint64(buf.internalBuffer[index:])
+	}
+	var raw [8]byte
+	if index < uint(len(buf.internalBuffer)) {
+		copy(raw[:], buf.internalBuffer[index:])
+	}
+	return binary.LittleEndian.Uint64(raw[:])
 }
 
 func (buf *Reader) ensureInBounds(numBits uint) error {
-	if buf.currentBit+numBits > buf.totalBits {
-		return fmt.Errorf("bitbuf attempt oob read by %d bits", (buf.currentBit+numBits)-buf.totalBits)
+	return buf.ensureAvailable("read", numBits)
+}
+
+// ensureAvailable checks that numBits following the cursor can be consumed by op.
+func (buf *Reader) ensureAvailable(op string, numBits uint) (err error) {
+	if buf.err != nil {
+		return buf.err
+	}
+
+	if buf.source != nil {
+		err = buf.ensureInStream(op, numBits)
+	} else if exceeds(buf.currentBit, numBits, buf.totalBits) {
+		err = newBoundsError(op, buf.currentBit, numBits, buf.totalBits, nil)
+	}
+
+	if err != nil && buf.sticky {
+		buf.err = err
+	}
+	return err
+}
+
+// unrepresentable returns a *BoundsError for an op whose extent overflows, latching it
+// as ensureAvailable does. It never reads from the source of a stream Reader.
+func (buf *Reader) unrepresentable(op string) error {
+	if buf.err != nil {
+		return buf.err
+	}
+	err := newBoundsError(op, buf.currentBit, math.MaxUint, buf.totalBits, nil)
+	if buf.sticky {
+		buf.err = err
+	}
+	return err
+}
+
+// ensureInStream is the stream Reader equivalent of ensureAvailable. It
+// refills the window from the source until the requested bits are available.
+func (buf *Reader) ensureInStream(op string, numBits uint) error {
+	if buf.currentBit < buf.bufferOffset<<3 {
+		return &StreamWindowError{Op: op, Position: buf.currentBit, WindowStart: buf.bufferOffset << 3}
+	}
+	if exceeds(buf.currentBit, numBits, buf.totalBits) {
+		buf.fill(numBits)
+	}
+	if exceeds(buf.currentBit, numBits, buf.totalBits) {
+		if buf.sourceErr != io.EOF {
+			return buf.sourceErr
+		}
+		if buf.currentBit >= buf.totalBits {
+			return newBoundsError(op, buf.currentBit, numBits, buf.totalBits, io.EOF)
+		}
+		return newBoundsError(op, buf.currentBit, numBits, buf.totalBits, io.ErrUnexpectedEOF)
 	}
 	return nil
 }
 
+// fill discards any bytes behind the cursor, then reads from the source
+// until the window holds the next numBits bits, or the source fails.
+func (buf *Reader) fill(numBits uint) {
+	if consumed := buf.currentBit>>3 - buf.bufferOffset; consumed > 0 {
+		n := copy(buf.internalBuffer, buf.internalBuffer[consumed:])
+		buf.internalBuffer = buf.internalBuffer[:n]
+		buf.bufferOffset += consumed
+	}
+
+	// saturate, rather than overflow, for huge numBits
+	end := uint(math.MaxUint)
+	if numBits <= math.MaxUint-7-buf.currentBit {
+		end = buf.currentBit + numBits + 7
+	}
+	required := end>>3 - buf.bufferOffset
+
+	emptyReads := 0
+	for uint(len(buf.internalBuffer)) < required && buf.sourceErr == nil {
+		// the window grows as data arrives, as numBits may be a length read from the data
+		if len(buf.internalBuffer) == cap(buf.internalBuffer) {
+			grow := len(buf.internalBuffer)
+			if grow < streamChunkSize {
+				grow = streamChunkSize
+			}
+			window := make([]byte, len(buf.internalBuffer), len(buf.internalBuffer)+grow)
+			copy(window, buf.internalBuffer)
+			buf.internalBuffer = window
+		}
+		n, err := buf.source.Read(buf.internalBuffer[len(buf.internalBuffer):cap(buf.internalBuffer)])
+		buf.internalBuffer = buf.internalBuffer[:len(buf.internalBuffer)+n]
+		if err != nil {
+			buf.sourceErr = err
+			break
+		}
+		if n > 0 {
+			emptyReads = 0
+			continue
+		}
+		if emptyReads++; emptyReads >= maxConsecutiveEmptyReads {
+			buf.sourceErr = io.ErrNoProgress
+		}
+	}
+
+	buf.totalBits = (buf.bufferOffset + uint(len(buf.internalBuffer))) << 3
+	// the window may hold zero padding where new data has now arrived
+	buf.windowBits = 0
+}
+
 // NewReader returns a new Bitbuf reader.
 func NewReader(data []byte) *Reader {
+	internalBuffer := make([]byte, len(data))
+	copy(internalBuffer, data)
+
 	return &Reader{
-		internalBuffer: *bytes.NewBuffer(data),
+		internalBuffer: internalBuffer,
 		totalBits:      uint(len(data) * 8),
 		currentBit:     0,
 	}
 }
+
+// NewStreamReader returns a new Bitbuf reader that reads from source on demand.
+// Only a window around the cursor is kept in memory, so seeking behind bytes
+// that have already been read is not supported.
+// Reads that run past the end of source return a *BoundsError wrapping io.EOF
+// if no bits of the value were available, or io.ErrUnexpectedEOF if some were.
+func NewStreamReader(source io.Reader) *Reader {
+	return &Reader{
+		internalBuffer: make([]byte, 0, streamChunkSize),
+		source:         source,
+	}
+}
@@ -1,8 +1,15 @@
 package bitbuf
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"reflect"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNewReader(t *testing.T) {
@@ -11,8 +18,230 @@ func TestNewReader(t *testing.T) {
 	}
 }
 
+func TestNewStreamReader(t *testing.T) {
+	if reflect.TypeOf(NewStreamReader(bytes.NewReader(nil))) != reflect.TypeOf(&Reader{}) {
+		t.Error("unexpected type returned")
+	}
+}
+
+func TestReader_Stream(t *testing.T) {
+	data := getTestBytes()
+	expected := NewReader(data)
+	sut := NewStreamReader(iotest.OneByteReader(bytes.NewReader(data)))
+
+	widths := []uint{1, 3, 7, 8, 13, 32, 5, 16, 31, 2, 24}
+	for i := 0; expected.BitsRead() < expected.Size(); i++ {
+		numBits := widths[i%len(widths)]
+		if remaining := expected.Size() - expected.BitsRead(); numBits > remaining {
+			numBits = remaining
+		}
+		expectedVal, err := expected.ReadUint32Bits(numBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		val, err := sut.ReadUint32Bits(numBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val != expectedVal {
+			t.Errorf("unexpected value at bit %d. expected: %d, but received: %d", expected.BitsRead()-numBits, expectedVal, val)
+		}
+		if sut.BitsRead() != expected.BitsRead() {
+			t.Errorf("expected: %d bits read, but received: %d", expected.BitsRead(), sut.BitsRead())
+		}
+	}
+
+	if _, err := sut.ReadUint8(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected: %v, but received: %v", io.EOF, err)
+	}
+}
+
+func TestReader_StreamUnexpectedEOF(t *testing.T) {
+	sut := NewStreamReader(bytes.NewReader([]byte{1, 2, 3}))
+
+	if _, err := sut.ReadUint16(); err != nil {
+		t.Error(err)
+	}
+	if _, err := sut.ReadUint32(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected: %v, but received: %v", io.ErrUnexpectedEOF, err)
+	}
+	if sut.BitsRead() != 16 {
+		t.Errorf("expected: %d bits read, but received: %d", 16, sut.BitsRead())
+	}
+}
+
+func TestReader_StreamSourceError(t *testing.T) {
+	sut := NewStreamReader(iotest.ErrReader(iotest.ErrTimeout))
+
+	if _, err := sut.ReadByte(); err != iotest.ErrTimeout {
+		t.Errorf("expected: %v, but received: %v", iotest.ErrTimeout, err)
+	}
+}
+
+func TestReader_StreamString(t *testing.T) {
+	sut := NewStreamReader(iotest.HalfReader(bytes.NewReader([]byte("hello\x00world"))))
+
+	if val, err := sut.ReadString(0); err != nil || val != "hello" {
+		t.Errorf("expected: %s, but received: %s (%v)", "hello", val, err)
+	}
+	if val, err := sut.ReadString(0); err != nil || val != "world" {
+		t.Errorf("expected: %s, but received: %s (%v)", "world", val, err)
+	}
+}
+
+func TestReader_ReadString_Unterminated(t *testing.T) {
+	data := []byte("hello\x00world")
+	for name, newReader := range map[string]func() *Reader{
+		"slice":  func() *Reader { return NewReader(data) },
+		"stream": func() *Reader { return NewStreamReader(bytes.NewReader(data)) },
+	} {
+		// The end of the data ends a string of unbounded length
+		sut := newReader()
+		sut.ReadString(0)
+		if val, err := sut.ReadString(0); err != nil || val != "world" {
+			t.Errorf("%s. expected: %s, but received: %s (%v)", name, "world", val, err)
+		}
+
+		// but not one expected to be terminated within maxLength
+		sut = newReader()
+		sut.ReadString(0)
+		if val, err := sut.ReadString(10); !errors.Is(err, ErrOutOfBounds) || val != "world" {
+			t.Errorf("%s. expected: %s, but received: %s (%v)", name, "world", val, err)
+		}
+	}
+}
+
+func TestReader_StickyErrors(t *testing.T) {
+	sut := NewReader([]byte{1, 2, 3})
+	sut.SetStickyErrors(true)
+
+	if _, err := sut.ReadUint16(); err != nil {
+		t.Error(err)
+	}
+	if _, err := sut.ReadUint16(); err == nil {
+		t.Error("expected oob read to fail")
+	}
+	// The remaining byte can no longer be read
+	if val, err := sut.ReadUint8(); err == nil || val != 0 {
+		t.Errorf("expected: %d, but received: %d (%v)", 0, val, err)
+	}
+	if sut.BitsRead() != 16 {
+		t.Errorf("expected: %d bits read, but received: %d", 16, sut.BitsRead())
+	}
+	if !sut.IsOverflowed() || sut.Err() == nil {
+		t.Error("expected reader to be overflowed")
+	}
+
+	sut.Reset()
+	if sut.IsOverflowed() || sut.Err() != nil {
+		t.Error("expected reset to clear overflow")
+	}
+	if _, err := sut.ReadUint8(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReader_StickyErrorsDisabled(t *testing.T) {
+	sut := NewReader([]byte{1, 2, 3})
+
+	if _, err := sut.ReadUint32(); err == nil {
+		t.Error("expected oob read to fail")
+	}
+	if val, err := sut.ReadUint8(); err != nil || val != 1 {
+		t.Errorf("expected: %d, but received: %d (%v)", 1, val, err)
+	}
+	if sut.IsOverflowed() || sut.Err() != nil {
+		t.Error("expected reader not to latch errors")
+	}
+}
+
+func TestReader_StickyErrorsStream(t *testing.T) {
+	sut := NewStreamReader(bytes.NewReader([]byte{1}))
+	sut.SetStickyErrors(true)
+
+	if _, err := sut.ReadUint16(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected: %v, but received: %v", io.ErrUnexpectedEOF, err)
+	}
+	if !sut.IsOverflowed() || !errors.Is(sut.Err(), io.ErrUnexpectedEOF) {
+		t.Error("expected reader to be overflowed")
+	}
+}
+
 func TestReader_ReadBits(t *testing.T) {
-	t.Skip()
+	sut := NewReader(getTestBytes())
+	sut.Seek(3)
+
+	expected := []byte{228, 22, 132, 4}
+	val, err := sut.ReadBits(29)
+	if err != nil {
+		t.Error(err)
+	}
+	if !bytes.Equal(val, expected) {
+		t.Errorf("expected: %v, but received: %v", expected, val)
+	}
+}
+
+func TestReader_ReadBits_OutOfBounds(t *testing.T) {
+	sut := NewReader([]byte{1, 2, 3})
+
+	_, err := sut.ReadBits(25)
+	var boundsErr *BoundsError
+	if !errors.As(err, &boundsErr) {
+		t.Fatalf("expected: %T, but received: %v", boundsErr, err)
+	}
+	if boundsErr.Op != "read" || boundsErr.BitPos != 0 || boundsErr.Requested != 25 || boundsErr.Available != 24 {
+		t.Errorf("unexpected bounds error: %+v", boundsErr)
+	}
+	if sut.BitsRead() != 0 {
+		t.Errorf("expected: %d bits read, but received: %d", 0, sut.BitsRead())
+	}
+}
+
+func TestReader_ReadBits_HugeLength(t *testing.T) {
+	// A length prefix of 0xffffffff bytes, followed by only 1 byte of data
+	data := []byte{0xff, 0xff, 0xff, 0xff, 'a'}
+	for name, sut := range map[string]*Reader{
+		"slice":  NewReader(data),
+		"stream": NewStreamReader(bytes.NewReader(data)),
+	} {
+		if _, err := sut.ReadLenPrefixedString(32); !errors.Is(err, ErrOutOfBounds) {
+			t.Errorf("%s. expected: %v, but received: %v", name, ErrOutOfBounds, err)
+		}
+	}
+
+	sut := NewReader(data)
+	if _, err := sut.ReadBits(math.MaxUint); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if _, err := sut.ReadBytes(math.MaxUint); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+
+	// An overflowing length is latched like any other out of bounds read
+	sut.SetStickyErrors(true)
+	if _, err := sut.ReadBytes(math.MaxUint); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if !sut.IsOverflowed() {
+		t.Error("expected reader to be overflowed")
+	}
+	if _, err := sut.ReadUint8(); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+}
+
+func TestReader_ReadOneBit(t *testing.T) {
+	sut := NewReader([]byte{2})
+
+	expected := []bool{false, true, false, false, false, false, false, false}
+	for _, e := range expected {
+		if val, err := sut.ReadOneBit(); err != nil || val != e {
+			t.Errorf("expected: %t, but received: %t (%v)", e, val, err)
+		}
+	}
+	if _, err := sut.ReadOneBit(); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
 }
 
 func TestReader_ReadByte(t *testing.T) {
@@ -182,6 +411,43 @@ func TestReader_ReadInt16(t *testing.T) {
 	}
 }
 
+func TestReader_ReadInt32Bits(t *testing.T) {
+	values := []int32{-1, 0, 1, -64, 63, -5, 17}
+	widths := []uint{7, 7, 7, 7, 7, 4, 32}
+
+	writer := NewWriter(16)
+	for i, v := range values {
+		if err := writer.WriteSignedBitInt32(v, widths[i]); err != nil {
+			t.Error(err)
+		}
+	}
+
+	sut := NewReader(writer.Data())
+	for i, expected := range values {
+		if val, err := sut.ReadInt32Bits(widths[i]); err != nil || val != expected {
+			t.Errorf("expected: %d, but received: %d (%v)", expected, val, err)
+		}
+	}
+}
+
+func TestReader_ReadSBitLong(t *testing.T) {
+	writer := NewWriter(8)
+	if err := writer.WriteSBitLong(-1, 7); err != nil {
+		t.Error(err)
+	}
+	if err := writer.WriteUBitLong(127, 7); err != nil {
+		t.Error(err)
+	}
+
+	sut := NewReader(writer.Data())
+	if val, err := sut.ReadSBitLong(7); err != nil || val != -1 {
+		t.Errorf("expected: %d, but received: %d (%v)", -1, val, err)
+	}
+	if val, err := sut.ReadUBitLong(7); err != nil || val != 127 {
+		t.Errorf("expected: %d, but received: %d (%v)", 127, val, err)
+	}
+}
+
 func TestReader_ReadInt32(t *testing.T) {
 	t.Skip()
 }
@@ -272,7 +538,528 @@ func TestReader_ReadUint32(t *testing.T) {
 }
 
 func TestReader_ReadUint64(t *testing.T) {
-	t.Skip()
+	data := getTestBytes()
+	sut := NewReader(data)
+	sut.Seek(7 << 3)
+
+	expected := binary.LittleEndian.Uint64(data[7:15])
+	if val, err := sut.ReadUint64(); err != nil || val != expected {
+		t.Errorf("expected: %d, but received: %d (%v)", expected, val, err)
+	}
+}
+
+func TestReader_ReadUint64Bits(t *testing.T) {
+	data := getTestBytes()
+
+	for startBit := uint(0); startBit < 8; startBit++ {
+		for numBits := uint(1); numBits <= 64; numBits++ {
+			sut := NewReader(data)
+			sut.Seek(int(startBit))
+
+			expected := readBitsNaive(data, startBit, numBits)
+			if val, err := sut.ReadUint64Bits(numBits); err != nil || val != expected {
+				t.Errorf("width %d at bit %d. expected: %d, but received: %d (%v)", numBits, startBit, expected, val, err)
+			}
+			if sut.BitsRead() != startBit+numBits {
+				t.Errorf("expected: %d bits read, but received: %d", startBit+numBits, sut.BitsRead())
+			}
+		}
+	}
+}
+
+func TestReader_ReadInt64Bits(t *testing.T) {
+	data := getTestBytes()
+
+	for startBit := uint(0); startBit < 8; startBit++ {
+		for numBits := uint(1); numBits <= 64; numBits++ {
+			sut := NewReader(data)
+			sut.Seek(int(startBit))
+
+			expected := int64(readBitsNaive(data, startBit, numBits))
+			if expected&(1<<(numBits-1)) != 0 {
+				expected -= int64(1<<(numBits-1)) * 2
+			}
+			if val, err := sut.ReadInt64Bits(numBits); err != nil || val != expected {
+				t.Errorf("width %d at bit %d. expected: %d, but received: %d (%v)", numBits, startBit, expected, val, err)
+			}
+		}
+	}
+}
+
+func TestReader_ReadUint32Bits_TooWide(t *testing.T) {
+	sut := NewReader(getTestBytes())
+
+	if _, err := sut.ReadUint32Bits(40); err == nil {
+		t.Error("expected read wider than 32 bits to fail")
+	}
+	if sut.BitsRead() != 0 {
+		t.Errorf("expected: %d bits read, but received: %d", 0, sut.BitsRead())
+	}
+}
+
+// readBitsNaive extracts numBits from data one bit at a time, as a reference
+func TestReader_Peek(t *testing.T) {
+	sut := NewReader(getTestBytes())
+	sut.Seek(3)
+
+	peekedBit, err := sut.PeekOneBit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	peekedUint, err := sut.PeekUint32Bits(19)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peekedBits, err := sut.PeekBits(29)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peekedBytes, err := sut.PeekBytes(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sut.BitsRead() != 3 {
+		t.Errorf("expected: %d, but received: %d", 3, sut.BitsRead())
+	}
+
+	if bit, _ := sut.ReadOneBit(); bit != peekedBit {
+		t.Errorf("expected: %t, but received: %t", bit, peekedBit)
+	}
+	sut.Seek(3)
+	if v, _ := sut.ReadUint32Bits(19); v != peekedUint {
+		t.Errorf("expected: %d, but received: %d", v, peekedUint)
+	}
+	sut.Seek(3)
+	if v, _ := sut.ReadBits(29); !bytes.Equal(v, peekedBits) {
+		t.Errorf("expected: %v, but received: %v", v, peekedBits)
+	}
+	sut.Seek(3)
+	if v, _ := sut.ReadBytes(4); !bytes.Equal(v, peekedBytes) {
+		t.Errorf("expected: %v, but received: %v", v, peekedBytes)
+	}
+}
+
+func TestReader_Peek_OutOfBounds(t *testing.T) {
+	sut := NewReader([]byte{1, 2})
+	sut.Seek(4)
+
+	if _, err := sut.PeekUint32Bits(13); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if _, err := sut.PeekBytes(2); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if sut.BitsRead() != 4 {
+		t.Errorf("expected: %d, but received: %d", 4, sut.BitsRead())
+	}
+	if v, err := sut.PeekUint32Bits(12); err != nil || v != 0x20 {
+		t.Errorf("expected: %d, but received: %d (%v)", 0x20, v, err)
+	}
+}
+
+func TestReader_Peek_StickyErrors(t *testing.T) {
+	for name, sut := range map[string]*Reader{
+		"slice":  NewReader([]byte{42}),
+		"stream": NewStreamReader(bytes.NewReader([]byte{42})),
+	} {
+		sut.SetStickyErrors(true)
+		if _, err := sut.PeekUint32Bits(16); !errors.Is(err, ErrOutOfBounds) {
+			t.Errorf("%s. expected: %v, but received: %v", name, ErrOutOfBounds, err)
+		}
+		if _, err := sut.PeekBytes(2); !errors.Is(err, ErrOutOfBounds) {
+			t.Errorf("%s. expected: %v, but received: %v", name, ErrOutOfBounds, err)
+		}
+		// A failed peek must not latch an error for the data that is there
+		if v, err := sut.ReadUint8(); err != nil || v != 42 {
+			t.Errorf("%s. expected: %d, but received: %d (%v)", name, 42, v, err)
+		}
+		if sut.Err() != nil {
+			t.Errorf("%s. expected: no latched error, but received: %v", name, sut.Err())
+		}
+	}
+}
+
+func TestReader_Peek_Stream(t *testing.T) {
+	data := getTestBytes()
+	sut := NewStreamReader(iotest.OneByteReader(bytes.NewReader(data)))
+	if _, err := sut.ReadBytes(10); err != nil {
+		t.Fatal(err)
+	}
+
+	peeked, err := sut.PeekBytes(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := sut.ReadBytes(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(peeked, read) || !bytes.Equal(read, data[10:30]) {
+		t.Errorf("expected: %v, but received: %v", data[10:30], peeked)
+	}
+}
+
+func TestReader_SeekBits(t *testing.T) {
+	sut := NewReader(getTestBytes())
+	size := int64(sut.Size())
+
+	cases := []struct {
+		offset   int64
+		whence   int
+		expected int64
+	}{
+		{12, io.SeekStart, 12},
+		{5, io.SeekCurrent, 17},
+		{-17, io.SeekCurrent, 0},
+		{-8, io.SeekEnd, size - 8},
+		{0, io.SeekEnd, size},
+	}
+	for _, c := range cases {
+		pos, err := sut.SeekBits(c.offset, c.whence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pos != c.expected || int64(sut.BitsRead()) != c.expected {
+			t.Errorf("expected: %d, but received: %d", c.expected, pos)
+		}
+	}
+}
+
+func TestReader_SeekBits_OutOfBounds(t *testing.T) {
+	sut := NewReader([]byte{1, 2})
+	if err := sut.Seek(9); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sut.SeekBits(-10, io.SeekCurrent); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	var boundsErr *BoundsError
+	if _, err := sut.SeekBits(1, io.SeekEnd); !errors.As(err, &boundsErr) || boundsErr.Op != "seek" {
+		t.Errorf("expected: seek *BoundsError, but received: %v", err)
+	}
+	if err := sut.Seek(17); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if _, err := sut.SeekBits(0, 7); err == nil {
+		t.Error("expected an error for an invalid whence, but received none")
+	}
+	if sut.BitsRead() != 9 {
+		t.Errorf("expected: %d, but received: %d", 9, sut.BitsRead())
+	}
+}
+
+func TestReader_HugeSkip(t *testing.T) {
+	for name, sut := range map[string]*Reader{
+		"slice":  NewReader([]byte{1, 2}),
+		"stream": NewStreamReader(bytes.NewReader([]byte{1, 2})),
+	} {
+		sut.ReadByte()
+		if err := sut.SkipBits(math.MaxUint - 4); !errors.Is(err, ErrOutOfBounds) {
+			t.Errorf("%s. expected: %v, but received: %v", name, ErrOutOfBounds, err)
+		}
+		if sut.BitsRead() != 8 {
+			t.Errorf("%s. expected: %d, but received: %d", name, 8, sut.BitsRead())
+		}
+	}
+}
+
+func TestReader_SeekBits_Overflow(t *testing.T) {
+	sut := NewReader([]byte{1, 2})
+	sut.SetStickyErrors(true)
+	sut.Seek(8)
+	var boundsErr *BoundsError
+	if _, err := sut.SeekBits(math.MaxInt64, io.SeekCurrent); !errors.As(err, &boundsErr) || boundsErr.Op != "seek" {
+		t.Errorf("expected: seek *BoundsError, but received: %v", err)
+	}
+	if sut.BitsRead() != 8 || !sut.IsOverflowed() {
+		t.Errorf("expected: %d bits read and an overflow, but received: %d (%v)", 8, sut.BitsRead(), sut.Err())
+	}
+
+	sut = NewReader([]byte{1, 2})
+	if _, err := sut.SeekBits(math.MaxInt64, io.SeekEnd); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if sut.BitsRead() != 0 {
+		t.Errorf("expected: %d, but received: %d", 0, sut.BitsRead())
+	}
+}
+
+func TestReader_SkipBits(t *testing.T) {
+	data := getTestBytes()
+	sut := NewReader(data)
+
+	if err := sut.SkipBits(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.SkipBytes(2); err != nil {
+		t.Fatal(err)
+	}
+	expected := uint32(readBitsNaive(data, 19, 13))
+	if v, _ := sut.ReadUint32Bits(13); v != expected {
+		t.Errorf("expected: %d, but received: %d", expected, v)
+	}
+
+	if err := sut.SkipBytes(uint(len(data))); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if sut.BitsRead() != 32 {
+		t.Errorf("expected: %d, but received: %d", 32, sut.BitsRead())
+	}
+}
+
+func TestReader_SkipBits_Stream(t *testing.T) {
+	data := getTestBytes()
+	sut := NewStreamReader(iotest.OneByteReader(bytes.NewReader(data)))
+
+	if err := sut.SkipBytes(40); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := sut.ReadByte(); v != data[40] {
+		t.Errorf("expected: %d, but received: %d", data[40], v)
+	}
+	var windowErr *StreamWindowError
+	if _, err := sut.SeekBits(0, io.SeekStart); !errors.As(err, &windowErr) || windowErr.Op != "seek" || windowErr.Position != 0 {
+		t.Errorf("expected: seek *StreamWindowError, but received: %v", err)
+	}
+	if _, err := sut.SeekBits(0, io.SeekEnd); err == nil {
+		t.Error("expected an error seeking relative to the end of a stream, but received none")
+	}
+	if err := sut.SkipBytes(uint(len(data))); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected: %v, but received: %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestReader_AlignToByte(t *testing.T) {
+	data := getTestBytes()
+	sut := NewReader(data)
+
+	if !sut.IsByteAligned() || sut.BitsToNextByte() != 0 {
+		t.Errorf("expected a new reader to be aligned")
+	}
+	if err := sut.AlignToByte(); err != nil || sut.BitsRead() != 0 {
+		t.Errorf("expected: %d, but received: %d (%v)", 0, sut.BitsRead(), err)
+	}
+
+	if _, err := sut.ReadUint32Bits(11); err != nil {
+		t.Fatal(err)
+	}
+	if sut.IsByteAligned() || sut.BitsToNextByte() != 5 {
+		t.Errorf("expected: %d, but received: %d", 5, sut.BitsToNextByte())
+	}
+	if err := sut.AlignToByte(); err != nil || sut.BitsRead() != 16 {
+		t.Errorf("expected: %d, but received: %d (%v)", 16, sut.BitsRead(), err)
+	}
+	if v, _ := sut.ReadByte(); v != data[2] {
+		t.Errorf("expected: %d, but received: %d", data[2], v)
+	}
+}
+
+func TestReader_ReadBits_Aligned(t *testing.T) {
+	data := getTestBytes()
+	for _, stream := range []bool{false, true} {
+		sut := NewReader(data)
+		if stream {
+			sut = NewStreamReader(iotest.OneByteReader(bytes.NewReader(data)))
+		}
+		if err := sut.SkipBytes(5); err != nil {
+			t.Fatal(err)
+		}
+
+		bits, err := sut.ReadBits(45)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := append(append([]byte{}, data[5:10]...), data[10]&31)
+		if !bytes.Equal(bits, expected) {
+			t.Errorf("expected: %v, but received: %v", expected, bits)
+		}
+		if sut.BitsRead() != 85 {
+			t.Errorf("expected: %d, but received: %d", 85, sut.BitsRead())
+		}
+	}
+}
+
+func TestReader_ConsecutiveReads(t *testing.T) {
+	data := getTestBytes()
+	sut := NewReader(data)
+
+	// Widths are chosen to exhaust the cached window part way through a read
+	widths := []uint{3, 64, 1, 57, 13, 8, 64, 64, 7, 32, 2, 60, 5}
+	pos := uint(0)
+	for i, numBits := range widths {
+		if i == 6 {
+			// Peeks and seeks back to the cursor must not disturb the window
+			if _, err := sut.PeekUint32Bits(17); err != nil {
+				t.Fatal(err)
+			}
+			sut.Seek(int(pos))
+		}
+		expected := readBitsNaive(data, pos, numBits)
+		val, err := sut.ReadUint64Bits(numBits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val != expected {
+			t.Errorf("unexpected value at bit %d. expected: %d, but received: %d", pos, expected, val)
+		}
+		pos += numBits
+	}
+}
+
+func TestReader_ReadBitsInto(t *testing.T) {
+	data := getTestBytes()
+	sut := NewReader(data)
+	sut.Seek(3)
+
+	dst := []byte{255, 255, 255, 255, 255}
+	if err := sut.ReadBitsInto(dst, 29); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{228, 22, 132, 4, 255}
+	if !bytes.Equal(dst, expected) {
+		t.Errorf("expected: %v, but received: %v", expected, dst)
+	}
+
+	if err := sut.ReadBitsInto(dst[:1], 9); err != io.ErrShortBuffer {
+		t.Errorf("expected: %v, but received: %v", io.ErrShortBuffer, err)
+	}
+	if err := sut.ReadBitsInto(make([]byte, 80), 80*8); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if sut.BitsRead() != 32 {
+		t.Errorf("expected: %d, but received: %d", 32, sut.BitsRead())
+	}
+}
+
+func TestReader_ReadBytesInto(t *testing.T) {
+	data := getTestBytes()
+	sut := NewReader(data)
+
+	dst := make([]byte, 6)
+	if err := sut.ReadBytesInto(dst); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dst, data[:6]) {
+		t.Errorf("expected: %v, but received: %v", data[:6], dst)
+	}
+}
+
+func TestReader_ReadStringInto(t *testing.T) {
+	sut := NewReader([]byte("hello\x00world\x00trailing"))
+	dst := make([]byte, 16)
+
+	if n, err := sut.ReadStringInto(dst, 0); err != nil || string(dst[:n]) != "hello" {
+		t.Errorf("expected: %s, but received: %s (%v)", "hello", dst[:n], err)
+	}
+	if n, err := sut.ReadStringInto(dst, 3); err != nil || string(dst[:n]) != "wor" {
+		t.Errorf("expected: %s, but received: %s (%v)", "wor", dst[:n], err)
+	}
+	if n, err := sut.ReadStringInto(dst, 0); err != nil || string(dst[:n]) != "ld" {
+		t.Errorf("expected: %s, but received: %s (%v)", "ld", dst[:n], err)
+	}
+	if _, err := sut.ReadStringInto(dst, 17); err != io.ErrShortBuffer {
+		t.Errorf("expected: %v, but received: %v", io.ErrShortBuffer, err)
+	}
+	if n, err := sut.ReadStringInto(dst, 0); err != nil || string(dst[:n]) != "trailing" {
+		t.Errorf("expected: %s, but received: %s (%v)", "trailing", dst[:n], err)
+	}
+}
+
+func TestReader_ReadInto_Allocs(t *testing.T) {
+	data := []byte("a string table entry\x00")
+	sut := NewReader(data)
+	dst := make([]byte, len(data))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		sut.Seek(0)
+		if err := sut.ReadBytesInto(dst); err != nil {
+			t.Fatal(err)
+		}
+		sut.Seek(3)
+		if err := sut.ReadBitsInto(dst, 101); err != nil {
+			t.Fatal(err)
+		}
+		sut.Seek(0)
+		if _, err := sut.ReadStringInto(dst, 0); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected: %d allocations, but received: %f", 0, allocs)
+	}
+}
+
+// benchmarkData returns a buffer large enough that benchmarks rarely need to reset
+func benchmarkData() []byte {
+	data := make([]byte, 64*1024)
+	for i := range data {
+		data[i] = byte(i*131 + 7)
+	}
+	return data
+}
+
+func BenchmarkReader_ReadUint64Bits(b *testing.B) {
+	data := benchmarkData()
+	for _, startBit := range []int{0, 3} {
+		for _, numBits := range []uint{1, 5, 8, 13, 32, 57, 64} {
+			b.Run(fmt.Sprintf("start%d/%d", startBit, numBits), func(b *testing.B) {
+				sut := NewReader(data)
+				limit := sut.Size() - numBits
+				sut.Seek(startBit)
+
+				b.SetBytes(int64(numBits+7) / 8)
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if sut.BitsRead() > limit {
+						sut.Seek(startBit)
+					}
+					if _, err := sut.ReadUint64Bits(numBits); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkReader_ReadBytes(b *testing.B) {
+	data := benchmarkData()
+	for _, startBit := range []int{0, 3} {
+		b.Run(fmt.Sprintf("start%d", startBit), func(b *testing.B) {
+			sut := NewReader(data)
+			b.SetBytes(256)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sut.Seek(startBit)
+				if _, err := sut.ReadBytes(256); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReader_ReadFloat32(b *testing.B) {
+	sut := NewReader(benchmarkData())
+	b.SetBytes(4)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if sut.BitsRead() > sut.Size()-32 {
+			sut.Reset()
+		}
+		if _, err := sut.ReadFloat32(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func readBitsNaive(data []byte, startBit uint, numBits uint) (value uint64) {
+	for i := uint(0); i < numBits; i++ {
+		bit := startBit + i
+		value |= uint64((data[bit>>3]>>(bit&7))&1) << i
+	}
+	return value
 }
 
 func getTestBytes() []byte {
@@ -0,0 +1,157 @@
+package sendtable
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrUnknownTable is returned when a data table prop refers to a table that doesn't exist
+	ErrUnknownTable = errors.New("unknown send table")
+	// ErrRecursiveTable is returned when a table contains itself
+	ErrRecursiveTable = errors.New("recursive send table")
+)
+
+// exclude identifies a prop excluded by a prop flagged FlagExclude
+type exclude struct {
+	tableName string
+	propName  string
+}
+
+// flattener holds the state of flattening a single table
+type flattener struct {
+	dt       *DataTables
+	excludes map[exclude]bool
+	visiting map[*Table]bool
+	props    []FlatProp
+}
+
+// Flatten returns the props of the named table in the order they are encoded, as the engine does:
+//   - props flagged FlagExclude, and the props they name anywhere in the hierarchy, are removed
+//   - props of data tables flagged FlagCollapsible are inlined in place
+//   - props of other data tables precede the props of the table referring to them
+//   - props are then ordered by Priority, moving props flagged FlagChangesOften to ChangesOftenPriority
+func (dt *DataTables) Flatten(tableName string) ([]FlatProp, error) {
+	table := dt.Table(tableName)
+	if table == nil {
+		return nil, fmt.Errorf("%w %s", ErrUnknownTable, tableName)
+	}
+
+	f := &flattener{
+		dt:       dt,
+		excludes: map[exclude]bool{},
+		visiting: map[*Table]bool{},
+	}
+	if err := f.gatherExcludes(table); err != nil {
+		return nil, err
+	}
+	if err := f.gatherProps(table); err != nil {
+		return nil, err
+	}
+	sortByPriority(f.props)
+	return f.props, nil
+}
+
+// gatherExcludes collects the props excluded by table and every table it refers to
+func (f *flattener) gatherExcludes(table *Table) error {
+	if f.visiting[table] {
+		return fmt.Errorf("%w %s", ErrRecursiveTable, table.Name)
+	}
+	f.visiting[table] = true
+	defer delete(f.visiting, table)
+
+	for _, prop := range table.Props {
+		if prop.Flags&FlagExclude != 0 {
+			f.excludes[exclude{tableName: prop.DataTableName, propName: prop.Name}] = true
+			continue
+		}
+		if prop.Type == PropTypeDataTable {
+			child, err := f.table(table, prop)
+			if err != nil {
+				return err
+			}
+			if err := f.gatherExcludes(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gatherProps appends the props of table, preceded by those of its non collapsible data tables
+func (f *flattener) gatherProps(table *Table) error {
+	var props []FlatProp
+	if err := f.iterateProps(table, &props); err != nil {
+		return err
+	}
+	f.props = append(f.props, props...)
+	return nil
+}
+
+// iterateProps appends the props of table and its collapsible data tables to props
+func (f *flattener) iterateProps(table *Table, props *[]FlatProp) error {
+	if f.visiting[table] {
+		return fmt.Errorf("%w %s", ErrRecursiveTable, table.Name)
+	}
+	f.visiting[table] = true
+	defer delete(f.visiting, table)
+
+	for _, prop := range table.Props {
+		if prop.Flags&(FlagExclude|FlagInsideArray) != 0 || f.excludes[exclude{tableName: table.Name, propName: prop.Name}] {
+			continue
+		}
+		if prop.Type != PropTypeDataTable {
+			*props = append(*props, FlatProp{Prop: prop, Table: table})
+			continue
+		}
+
+		child, err := f.table(table, prop)
+		if err != nil {
+			return err
+		}
+		if prop.Flags&FlagCollapsible != 0 {
+			err = f.iterateProps(child, props)
+		} else {
+			err = f.gatherProps(child)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// table returns the table referred to by a data table prop of parent
+func (f *flattener) table(parent *Table, prop *Prop) (*Table, error) {
+	table := f.dt.Table(prop.DataTableName)
+	if table == nil {
+		return nil, fmt.Errorf("%w %s, referred to by %s.%s", ErrUnknownTable, prop.DataTableName, parent.Name, prop.Name)
+	}
+	return table, nil
+}
+
+// sortByPriority moves props to the front in ascending order of priority.
+// Props of equal priority are moved in turn, so may not keep their relative order.
+func sortByPriority(props []FlatProp) {
+	priorities := []int{ChangesOftenPriority}
+	seen := map[int]bool{ChangesOftenPriority: true}
+	for _, prop := range props {
+		if !seen[prop.Prop.Priority] {
+			seen[prop.Prop.Priority] = true
+			priorities = append(priorities, prop.Prop.Priority)
+		}
+	}
+	sort.Ints(priorities)
+
+	start := 0
+	for _, priority := range priorities {
+		for i := start; i < len(props); i++ {
+			prop := props[i].Prop
+			if prop.Priority == priority || (priority == ChangesOftenPriority && prop.Flags&FlagChangesOften != 0) {
+				props[start], props[i] = props[i], props[start]
+				start++
+			}
+		}
+	}
+}
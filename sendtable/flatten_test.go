@@ -0,0 +1,110 @@
+package sendtable
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// syntheticTables returns a small player hierarchy exercising every flattening rule
+func syntheticTables() []*Table {
+	element := &Prop{Type: PropTypeInt, Name: "000", Flags: FlagInsideArray | FlagUnsigned, NumBits: 21, Priority: DefaultPriority}
+	return []*Table{
+		{Name: "DT_BaseEntity", NeedsDecoder: true, Props: []*Prop{
+			{Type: PropTypeVector, Name: "m_vecOrigin", Flags: FlagCoordMP | FlagChangesOften, Priority: DefaultPriority},
+			{Type: PropTypeInt, Name: "m_nModelIndex", NumBits: 13, Priority: DefaultPriority},
+			{Type: PropTypeInt, Name: "m_iTeamNum", NumBits: 6, Priority: DefaultPriority},
+			{Type: PropTypeDataTable, Name: "m_Collision", DataTableName: "DT_CollisionProperty", Priority: DefaultPriority},
+		}},
+		{Name: "DT_CollisionProperty", Props: []*Prop{
+			{Type: PropTypeVector, Name: "m_vecMins", Flags: FlagCoord, Priority: DefaultPriority},
+			{Type: PropTypeVector, Name: "m_vecMaxs", Flags: FlagCoord, Priority: DefaultPriority},
+		}},
+		{Name: "DT_LocalPlayerExclusive", Props: []*Prop{
+			{Type: PropTypeFloat, Name: "m_flFOVTime", LowValue: 0, HighValue: 10, NumBits: 10, Priority: DefaultPriority},
+		}},
+		{Name: "DT_BasePlayer", Props: []*Prop{
+			{Type: PropTypeDataTable, Name: "baseclass", DataTableName: "DT_BaseEntity", Priority: DefaultPriority},
+			{Type: PropTypeInt, Name: "m_nModelIndex", Flags: FlagExclude, DataTableName: "DT_BaseEntity", Priority: DefaultPriority},
+			{Type: PropTypeInt, Name: "m_iHealth", Flags: FlagVarInt, NumBits: 32, Priority: DefaultPriority},
+			{Type: PropTypeDataTable, Name: "localdata", Flags: FlagCollapsible, DataTableName: "DT_LocalPlayerExclusive", Priority: DefaultPriority},
+			element,
+			{Type: PropTypeArray, Name: "m_hMyWeapons", NumElements: 48, ArrayElement: element, Priority: DefaultPriority},
+			{Type: PropTypeFloat, Name: "m_flSimulationTime", Flags: FlagChangesOften | FlagNoScale, Priority: DefaultPriority},
+		}},
+	}
+}
+
+func flatNames(props []FlatProp) []string {
+	names := make([]string, len(props))
+	for i, prop := range props {
+		names[i] = prop.Name()
+	}
+	return names
+}
+
+func TestDataTables_Flatten(t *testing.T) {
+	dt, err := NewDataTables(syntheticTables(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Props of DT_CollisionProperty and DT_BaseEntity precede those of DT_BasePlayer,
+	// then m_vecOrigin and m_flSimulationTime are swapped to the front
+	props, err := dt.Flatten("DT_BasePlayer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"DT_BaseEntity.m_vecOrigin",
+		"DT_BasePlayer.m_flSimulationTime",
+		"DT_CollisionProperty.m_vecMins",
+		"DT_BaseEntity.m_iTeamNum",
+		"DT_BasePlayer.m_iHealth",
+		"DT_LocalPlayerExclusive.m_flFOVTime",
+		"DT_BasePlayer.m_hMyWeapons",
+		"DT_CollisionProperty.m_vecMaxs",
+	}
+	if actual := flatNames(props); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %v, but received: %v", expected, actual)
+	}
+}
+
+func TestDataTables_Flatten_UnknownTable(t *testing.T) {
+	tables := syntheticTables()
+	tables = tables[:1]
+	if _, err := NewDataTables(tables, []*ServerClass{{Name: "CBaseEntity", DataTableName: "DT_BaseEntity"}}); !errors.Is(err, ErrUnknownTable) {
+		t.Errorf("expected: %v, but received: %v", ErrUnknownTable, err)
+	}
+
+	dt, _ := NewDataTables(syntheticTables(), nil)
+	if _, err := dt.Flatten("DT_Missing"); !errors.Is(err, ErrUnknownTable) {
+		t.Errorf("expected: %v, but received: %v", ErrUnknownTable, err)
+	}
+}
+
+func TestDataTables_Flatten_RecursiveTable(t *testing.T) {
+	tables := []*Table{
+		{Name: "DT_A", Props: []*Prop{{Type: PropTypeDataTable, Name: "b", DataTableName: "DT_B"}}},
+		{Name: "DT_B", Props: []*Prop{{Type: PropTypeDataTable, Name: "a", Flags: FlagCollapsible, DataTableName: "DT_A"}}},
+	}
+	dt, _ := NewDataTables(tables, nil)
+	if _, err := dt.Flatten("DT_A"); !errors.Is(err, ErrRecursiveTable) {
+		t.Errorf("expected: %v, but received: %v", ErrRecursiveTable, err)
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	props := []FlatProp{}
+	table := &Table{Name: "DT_Test"}
+	for i, priority := range []int{128, 1, 128, 64, 1} {
+		props = append(props, FlatProp{Prop: &Prop{Name: string(rune('a' + i)), Priority: priority}, Table: table})
+	}
+	props[2].Prop.Flags = FlagChangesOften
+
+	sortByPriority(props)
+	expected := []string{"DT_Test.b", "DT_Test.e", "DT_Test.c", "DT_Test.d", "DT_Test.a"}
+	if actual := flatNames(props); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected: %v, but received: %v", expected, actual)
+	}
+}
@@ -0,0 +1,248 @@
+package sendtable
+
+import (
+	"fmt"
+
+	"github.com/galaco/bitbuf"
+)
+
+// DataTables are the tables and server classes sent in a dem_datatables frame
+type DataTables struct {
+	Tables  []*Table
+	Classes []*ServerClass
+
+	tables map[string]*Table
+}
+
+// NewDataTables returns DataTables for tables and classes, with every class flattened
+func NewDataTables(tables []*Table, classes []*ServerClass) (*DataTables, error) {
+	dt := &DataTables{
+		Tables:  tables,
+		Classes: classes,
+		tables:  make(map[string]*Table, len(tables)),
+	}
+	for _, table := range tables {
+		dt.tables[table.Name] = table
+	}
+	for _, class := range classes {
+		props, err := dt.Flatten(class.DataTableName)
+		if err != nil {
+			return nil, fmt.Errorf("server class %s: %w", class.Name, err)
+		}
+		class.Props = props
+		class.indexProps()
+	}
+	return dt, nil
+}
+
+// Table returns the table named name, or nil if there is none
+func (dt *DataTables) Table(name string) *Table {
+	return dt.tables[name]
+}
+
+// Class returns the server class with the given ID, or nil if there is none
+func (dt *DataTables) Class(id uint16) *ServerClass {
+	if int(id) < len(dt.Classes) && dt.Classes[id].ID == id {
+		return dt.Classes[id]
+	}
+	for _, class := range dt.Classes {
+		if class.ID == id {
+			return class
+		}
+	}
+	return nil
+}
+
+// Parse reads the tables and server classes of a dem_datatables frame, and flattens every class.
+func Parse(buf *bitbuf.Reader) (*DataTables, error) {
+	var tables []*Table
+	for {
+		more, err := buf.ReadOneBit()
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			break
+		}
+		needsDecoder, err := buf.ReadOneBit()
+		if err != nil {
+			return nil, err
+		}
+		table, err := ReadTable(buf)
+		if err != nil {
+			return nil, err
+		}
+		table.NeedsDecoder = needsDecoder
+		tables = append(tables, table)
+	}
+
+	numClasses, err := buf.ReadUint16()
+	if err != nil {
+		return nil, err
+	}
+	classes := make([]*ServerClass, numClasses)
+	for i := range classes {
+		class := &ServerClass{}
+		if class.ID, err = buf.ReadUint16(); err != nil {
+			return nil, err
+		}
+		if class.Name, err = buf.ReadString(0); err != nil {
+			return nil, err
+		}
+		if class.DataTableName, err = buf.ReadString(0); err != nil {
+			return nil, err
+		}
+		classes[i] = class
+	}
+
+	return NewDataTables(tables, classes)
+}
+
+// ReadTable reads a single table, as sent in svc_SendTable and dem_datatables.
+// NeedsDecoder is sent separately, so is not set.
+func ReadTable(buf *bitbuf.Reader) (table *Table, err error) {
+	table = &Table{}
+	if table.Name, err = buf.ReadString(0); err != nil {
+		return nil, err
+	}
+	numProps, err := buf.ReadUint32Bits(NumPropsBits)
+	if err != nil {
+		return nil, err
+	}
+
+	table.Props = make([]*Prop, numProps)
+	for i := range table.Props {
+		prop, err := readProp(buf)
+		if err != nil {
+			return nil, fmt.Errorf("send table %s: %w", table.Name, err)
+		}
+		if prop.Type == PropTypeArray {
+			if i == 0 {
+				return nil, fmt.Errorf("send table %s: array prop %s has no element prop", table.Name, prop.Name)
+			}
+			prop.ArrayElement = table.Props[i-1]
+		}
+		table.Props[i] = prop
+	}
+	return table, nil
+}
+
+// readProp reads a single prop of a table
+func readProp(buf *bitbuf.Reader) (prop *Prop, err error) {
+	prop = &Prop{Priority: DefaultPriority}
+	propType, err := buf.ReadUint32Bits(PropTypeBits)
+	if err != nil {
+		return nil, err
+	}
+	prop.Type = PropType(propType)
+	if prop.Name, err = buf.ReadString(0); err != nil {
+		return nil, err
+	}
+	rawFlags, err := buf.ReadUint32Bits(FlagBits)
+	if err != nil {
+		return nil, err
+	}
+	prop.Flags = NormalizeFlags(rawFlags, prop.Type)
+
+	switch {
+	case prop.Type == PropTypeDataTable, prop.Flags&FlagExclude != 0:
+		prop.DataTableName, err = buf.ReadString(0)
+	case prop.Type == PropTypeArray:
+		prop.NumElements, err = buf.ReadUint32Bits(NumElementsBits)
+	default:
+		if prop.LowValue, err = buf.ReadFloat32(); err != nil {
+			return nil, err
+		}
+		if prop.HighValue, err = buf.ReadFloat32(); err != nil {
+			return nil, err
+		}
+		prop.NumBits, err = buf.ReadUint32Bits(NumBitsBits)
+	}
+	return prop, err
+}
+
+// Write writes the tables and server classes as a dem_datatables frame
+func (dt *DataTables) Write(writer *bitbuf.Writer) error {
+	for _, table := range dt.Tables {
+		if err := writer.WriteOneBit(true); err != nil {
+			return err
+		}
+		if err := writer.WriteOneBit(table.NeedsDecoder); err != nil {
+			return err
+		}
+		if err := WriteTable(writer, table); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteOneBit(false); err != nil {
+		return err
+	}
+
+	if err := writeBounded(writer, uint32(len(dt.Classes)), 16); err != nil {
+		return err
+	}
+	for _, class := range dt.Classes {
+		if err := writer.WriteUint16(class.ID); err != nil {
+			return err
+		}
+		if err := writer.WriteCString(class.Name); err != nil {
+			return err
+		}
+		if err := writer.WriteCString(class.DataTableName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTable writes a single table, as sent in svc_SendTable and dem_datatables.
+// NeedsDecoder is sent separately, so is not written.
+func WriteTable(writer *bitbuf.Writer, table *Table) error {
+	if err := writer.WriteCString(table.Name); err != nil {
+		return err
+	}
+	if err := writeBounded(writer, uint32(len(table.Props)), NumPropsBits); err != nil {
+		return err
+	}
+	for _, prop := range table.Props {
+		if err := writeProp(writer, prop); err != nil {
+			return fmt.Errorf("send table %s: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// writeProp writes a single prop of a table
+func writeProp(writer *bitbuf.Writer, prop *Prop) error {
+	if err := writeBounded(writer, uint32(prop.Type), PropTypeBits); err != nil {
+		return err
+	}
+	if err := writer.WriteCString(prop.Name); err != nil {
+		return err
+	}
+	if err := writer.WriteUnsignedBitInt32(RawFlags(prop.Flags, prop.Type), FlagBits); err != nil {
+		return err
+	}
+
+	switch {
+	case prop.Type == PropTypeDataTable, prop.Flags&FlagExclude != 0:
+		return writer.WriteCString(prop.DataTableName)
+	case prop.Type == PropTypeArray:
+		return writeBounded(writer, prop.NumElements, NumElementsBits)
+	}
+	if err := writer.WriteFloat32(prop.LowValue); err != nil {
+		return err
+	}
+	if err := writer.WriteFloat32(prop.HighValue); err != nil {
+		return err
+	}
+	return writeBounded(writer, prop.NumBits, NumBitsBits)
+}
+
+// writeBounded writes value in numBits bits, failing if it doesn't fit
+func writeBounded(writer *bitbuf.Writer, value uint32, numBits uint) error {
+	if uint64(value) >= uint64(1)<<numBits {
+		return fmt.Errorf("sendtable value %d does not fit in %d bits", value, numBits)
+	}
+	return writer.WriteUnsignedBitInt32(value, numBits)
+}
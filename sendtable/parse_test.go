@@ -0,0 +1,85 @@
+package sendtable
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+)
+
+func syntheticClasses() []*ServerClass {
+	return []*ServerClass{
+		{ID: 0, Name: "CBaseEntity", DataTableName: "DT_BaseEntity"},
+		{ID: 1, Name: "CBasePlayer", DataTableName: "DT_BasePlayer"},
+	}
+}
+
+func TestParse(t *testing.T) {
+	expected, err := NewDataTables(syntheticTables(), syntheticClasses())
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := expected.Write(writer); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := bitbuf.NewReader(writer.Data())
+	actual, err := Parse(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual.Tables, expected.Tables) {
+		t.Errorf("expected: %+v, but received: %+v", expected.Tables, actual.Tables)
+	}
+	for i, class := range actual.Classes {
+		if !reflect.DeepEqual(flatNames(class.Props), flatNames(expected.Classes[i].Props)) {
+			t.Errorf("expected: %v, but received: %v", flatNames(expected.Classes[i].Props), flatNames(class.Props))
+		}
+	}
+	if reader.BitsRead() != writer.BitsWritten() {
+		t.Errorf("expected: %d bits read, but received: %d", writer.BitsWritten(), reader.BitsRead())
+	}
+}
+
+func TestParse_Truncated(t *testing.T) {
+	dt, _ := NewDataTables(syntheticTables(), syntheticClasses())
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := dt.Write(writer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Parse(bitbuf.NewReader(writer.Data()[:40])); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestReadTable_ArrayWithoutElement(t *testing.T) {
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	table := &Table{Name: "DT_Bad", Props: []*Prop{{Type: PropTypeArray, Name: "m_Array", NumElements: 2}}}
+	if err := WriteTable(writer, table); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTable(bitbuf.NewReader(writer.Data())); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestWriteTable_ValueTooLarge(t *testing.T) {
+	table := &Table{Name: "DT_Bad", Props: []*Prop{{Type: PropTypeInt, Name: "m_iValue", NumBits: 1 << NumBitsBits}}}
+	if err := WriteTable(bitbuf.NewGrowableWriter(0, 0), table); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestDataTables_Class(t *testing.T) {
+	dt, err := NewDataTables(syntheticTables(), syntheticClasses())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if class := dt.Class(1); class == nil || class.Name != "CBasePlayer" {
+		t.Errorf("expected: %s, but received: %+v", "CBasePlayer", class)
+	}
+	if class := dt.Class(2); class != nil {
+		t.Errorf("expected: nil, but received: %+v", class)
+	}
+}
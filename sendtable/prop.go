@@ -0,0 +1,366 @@
+package sendtable
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/galaco/bitbuf"
+)
+
+const (
+	// StringLengthBits is the width of a string prop's length
+	StringLengthBits = 9
+	// MaxStringLength is the maximum length of a string prop, in bytes
+	MaxStringLength = 1<<StringLengthBits - 1
+)
+
+// DecodeProp reads a single value of prop. The type of the value returned depends on prop.Type:
+//   - PropTypeInt: int32. Unsigned values are returned as their bit pattern, as the engine does.
+//   - PropTypeInt64: int64
+//   - PropTypeFloat: float32
+//   - PropTypeVector: [3]float32
+//   - PropTypeVectorXY: [2]float32
+//   - PropTypeString: string
+//   - PropTypeArray: []interface{}, holding values of prop.ArrayElement
+func DecodeProp(buf *bitbuf.Reader, prop *Prop) (interface{}, error) {
+	switch prop.Type {
+	case PropTypeInt:
+		return decodeInt(buf, prop)
+	case PropTypeInt64:
+		return decodeInt64(buf, prop)
+	case PropTypeFloat:
+		return decodeFloat(buf, prop)
+	case PropTypeVector:
+		return decodeVector(buf, prop)
+	case PropTypeVectorXY:
+		var vec [2]float32
+		var err error
+		if vec[0], err = decodeFloat(buf, prop); err != nil {
+			return vec, err
+		}
+		vec[1], err = decodeFloat(buf, prop)
+		return vec, err
+	case PropTypeString:
+		length, err := buf.ReadUint32Bits(StringLengthBits)
+		if err != nil {
+			return "", err
+		}
+		value, err := buf.ReadBytes(uint(length))
+		return string(value), err
+	case PropTypeArray:
+		return decodeArray(buf, prop)
+	}
+	return nil, fmt.Errorf("sendtable prop %s: cannot decode %s", prop.Name, prop.Type)
+}
+
+// EncodeProp writes a single value of prop. value must be of the type DecodeProp returns for prop.
+func EncodeProp(writer *bitbuf.Writer, prop *Prop, value interface{}) error {
+	switch value := value.(type) {
+	case int32:
+		if prop.Type == PropTypeInt {
+			return encodeInt(writer, prop, value)
+		}
+	case int64:
+		if prop.Type == PropTypeInt64 {
+			return encodeInt64(writer, prop, value)
+		}
+	case float32:
+		if prop.Type == PropTypeFloat {
+			return encodeFloat(writer, prop, value)
+		}
+	case [3]float32:
+		if prop.Type == PropTypeVector {
+			return encodeVector(writer, prop, value)
+		}
+	case [2]float32:
+		if prop.Type == PropTypeVectorXY {
+			if err := encodeFloat(writer, prop, value[0]); err != nil {
+				return err
+			}
+			return encodeFloat(writer, prop, value[1])
+		}
+	case string:
+		if prop.Type == PropTypeString {
+			if len(value) > MaxStringLength {
+				return fmt.Errorf("sendtable prop %s: string of %d bytes is longer than %d", prop.Name, len(value), MaxStringLength)
+			}
+			if err := writer.WriteUnsignedBitInt32(uint32(len(value)), StringLengthBits); err != nil {
+				return err
+			}
+			return writer.WriteString(value)
+		}
+	case []interface{}:
+		if prop.Type == PropTypeArray {
+			return encodeArray(writer, prop, value)
+		}
+	}
+	return fmt.Errorf("sendtable prop %s: cannot encode %T as %s", prop.Name, value, prop.Type)
+}
+
+func decodeInt(buf *bitbuf.Reader, prop *Prop) (int32, error) {
+	unsigned := prop.Flags&FlagUnsigned != 0
+	if prop.Flags&FlagVarInt != 0 {
+		if unsigned {
+			value, err := buf.ReadVarInt32()
+			return int32(value), err
+		}
+		return buf.ReadSignedVarInt32()
+	}
+	if unsigned {
+		value, err := buf.ReadUint32Bits(uint(prop.NumBits))
+		return int32(value), err
+	}
+	return buf.ReadInt32Bits(uint(prop.NumBits))
+}
+
+func encodeInt(writer *bitbuf.Writer, prop *Prop, value int32) error {
+	unsigned := prop.Flags&FlagUnsigned != 0
+	if prop.Flags&FlagVarInt != 0 {
+		if unsigned {
+			return writer.WriteVarInt32(uint32(value))
+		}
+		return writer.WriteSignedVarInt32(value)
+	}
+	if unsigned {
+		return writer.WriteUnsignedBitInt32(uint32(value), uint(prop.NumBits))
+	}
+	return writer.WriteSignedBitInt32(value, uint(prop.NumBits))
+}
+
+// int64HighBits returns the width of the high part of a fixed width int64 prop,
+// which follows the low 32 bits, and a sign bit if the prop is signed
+func int64HighBits(prop *Prop) (uint, error) {
+	lowBits := uint32(32)
+	if prop.Flags&FlagUnsigned == 0 {
+		lowBits++
+	}
+	if prop.NumBits < lowBits || prop.NumBits > 64 {
+		return 0, fmt.Errorf("sendtable prop %s: %d bits is not a valid int64 width", prop.Name, prop.NumBits)
+	}
+	return uint(prop.NumBits - lowBits), nil
+}
+
+func decodeInt64(buf *bitbuf.Reader, prop *Prop) (int64, error) {
+	unsigned := prop.Flags&FlagUnsigned != 0
+	if prop.Flags&FlagVarInt != 0 {
+		if unsigned {
+			value, err := buf.ReadVarInt64()
+			return int64(value), err
+		}
+		return buf.ReadSignedVarInt64()
+	}
+
+	highBits, err := int64HighBits(prop)
+	if err != nil {
+		return 0, err
+	}
+	negative := false
+	if !unsigned {
+		if negative, err = buf.ReadOneBit(); err != nil {
+			return 0, err
+		}
+	}
+	low, err := buf.ReadUint32()
+	if err != nil {
+		return 0, err
+	}
+	high, err := buf.ReadUint32Bits(highBits)
+	if err != nil {
+		return 0, err
+	}
+	value := int64(uint64(high)<<32 | uint64(low))
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+func encodeInt64(writer *bitbuf.Writer, prop *Prop, value int64) error {
+	unsigned := prop.Flags&FlagUnsigned != 0
+	if prop.Flags&FlagVarInt != 0 {
+		if unsigned {
+			return writer.WriteVarInt64(uint64(value))
+		}
+		return writer.WriteSignedVarInt64(value)
+	}
+
+	highBits, err := int64HighBits(prop)
+	if err != nil {
+		return err
+	}
+	magnitude := uint64(value)
+	if !unsigned {
+		if err := writer.WriteOneBit(value < 0); err != nil {
+			return err
+		}
+		if value < 0 {
+			magnitude = uint64(-value)
+		}
+	}
+	if err := writer.WriteUint32(uint32(magnitude)); err != nil {
+		return err
+	}
+	return writer.WriteUnsignedBitInt32(uint32(magnitude>>32), highBits)
+}
+
+// quantizedSteps returns the largest value of a quantized float prop
+func quantizedSteps(prop *Prop) (uint32, error) {
+	if prop.NumBits == 0 || prop.NumBits > 32 {
+		return 0, fmt.Errorf("sendtable prop %s: %d bits is not a valid quantized float width", prop.Name, prop.NumBits)
+	}
+	return uint32(uint64(1)<<prop.NumBits - 1), nil
+}
+
+// coordMPFlags and cellCoordFlags select an encoding, whose variant is chosen by
+// which of the flags are set
+const (
+	coordMPFlags   = FlagCoordMP | FlagCoordMPLowPrecision | FlagCoordMPIntegral
+	cellCoordFlags = FlagCellCoord | FlagCellCoordLowPrecision | FlagCellCoordIntegral
+)
+
+func decodeFloat(buf *bitbuf.Reader, prop *Prop) (float32, error) {
+	flags := prop.Flags
+	switch {
+	case flags&FlagCoord != 0:
+		return buf.ReadBitCoord()
+	case flags&coordMPFlags != 0:
+		return buf.ReadBitCoordMP(flags&FlagCoordMPIntegral != 0, flags&FlagCoordMPLowPrecision != 0)
+	case flags&FlagNoScale != 0:
+		return buf.ReadFloat32()
+	case flags&FlagNormal != 0:
+		return buf.ReadBitNormal()
+	case flags&cellCoordFlags != 0:
+		return buf.ReadBitCellCoord(uint(prop.NumBits), flags&FlagCellCoordIntegral != 0, flags&FlagCellCoordLowPrecision != 0)
+	}
+
+	steps, err := quantizedSteps(prop)
+	if err != nil {
+		return 0, err
+	}
+	interp, err := buf.ReadUint32Bits(uint(prop.NumBits))
+	if err != nil {
+		return 0, err
+	}
+	value := float32(interp) / float32(steps)
+	return prop.LowValue + (prop.HighValue-prop.LowValue)*value, nil
+}
+
+func encodeFloat(writer *bitbuf.Writer, prop *Prop, value float32) error {
+	flags := prop.Flags
+	switch {
+	case flags&FlagCoord != 0:
+		return writer.WriteBitCoord(value)
+	case flags&coordMPFlags != 0:
+		return writer.WriteBitCoordMP(value, flags&FlagCoordMPIntegral != 0, flags&FlagCoordMPLowPrecision != 0)
+	case flags&FlagNoScale != 0:
+		return writer.WriteFloat32(value)
+	case flags&FlagNormal != 0:
+		return writer.WriteBitNormal(value)
+	case flags&cellCoordFlags != 0:
+		return writer.WriteBitCellCoord(value, uint(prop.NumBits), flags&FlagCellCoordIntegral != 0, flags&FlagCellCoordLowPrecision != 0)
+	}
+
+	steps, err := quantizedSteps(prop)
+	if err != nil {
+		return err
+	}
+	// Values outside of the range are clamped, and values within it rounded to the nearest step
+	var interp uint32
+	switch {
+	case value <= prop.LowValue:
+		interp = 0
+	case value >= prop.HighValue:
+		interp = steps
+	default:
+		scaled := float64(value-prop.LowValue) * float64(steps) / float64(prop.HighValue-prop.LowValue)
+		interp = uint32(math.Min(math.Floor(scaled+0.5), float64(steps)))
+	}
+	return writer.WriteUnsignedBitInt32(interp, uint(prop.NumBits))
+}
+
+func decodeVector(buf *bitbuf.Reader, prop *Prop) (vec [3]float32, err error) {
+	if vec[0], err = decodeFloat(buf, prop); err != nil {
+		return vec, err
+	}
+	if vec[1], err = decodeFloat(buf, prop); err != nil {
+		return vec, err
+	}
+	if prop.Flags&FlagNormal == 0 {
+		vec[2], err = decodeFloat(buf, prop)
+		return vec, err
+	}
+
+	// Normals only send the sign of z, as it can be derived from x and y
+	negative, err := buf.ReadOneBit()
+	if err != nil {
+		return vec, err
+	}
+	if lengthSqr := vec[0]*vec[0] + vec[1]*vec[1]; lengthSqr < 1 {
+		vec[2] = float32(math.Sqrt(float64(1 - lengthSqr)))
+	}
+	if negative {
+		vec[2] = -vec[2]
+	}
+	return vec, nil
+}
+
+func encodeVector(writer *bitbuf.Writer, prop *Prop, vec [3]float32) error {
+	if err := encodeFloat(writer, prop, vec[0]); err != nil {
+		return err
+	}
+	if err := encodeFloat(writer, prop, vec[1]); err != nil {
+		return err
+	}
+	if prop.Flags&FlagNormal == 0 {
+		return encodeFloat(writer, prop, vec[2])
+	}
+	return writer.WriteOneBit(vec[2] <= -bitbuf.NormalResolution)
+}
+
+// arrayCountBits returns the width of the element count of an array prop, Q_log2(NumElements)+1
+func arrayCountBits(prop *Prop) uint {
+	if prop.NumElements == 0 {
+		return 1
+	}
+	return uint(bits.Len32(prop.NumElements))
+}
+
+func decodeArray(buf *bitbuf.Reader, prop *Prop) ([]interface{}, error) {
+	if prop.ArrayElement == nil {
+		return nil, fmt.Errorf("sendtable prop %s: array has no element prop", prop.Name)
+	}
+	count, err := buf.ReadUint32Bits(arrayCountBits(prop))
+	if err != nil {
+		return nil, err
+	}
+	if count > prop.NumElements {
+		return nil, fmt.Errorf("sendtable prop %s: %d elements is more than %d", prop.Name, count, prop.NumElements)
+	}
+
+	values := make([]interface{}, count)
+	for i := range values {
+		if values[i], err = DecodeProp(buf, prop.ArrayElement); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+func encodeArray(writer *bitbuf.Writer, prop *Prop, values []interface{}) error {
+	if prop.ArrayElement == nil {
+		return fmt.Errorf("sendtable prop %s: array has no element prop", prop.Name)
+	}
+	if len(values) > int(prop.NumElements) {
+		return fmt.Errorf("sendtable prop %s: %d elements is more than %d", prop.Name, len(values), prop.NumElements)
+	}
+	if err := writer.WriteUnsignedBitInt32(uint32(len(values)), arrayCountBits(prop)); err != nil {
+		return err
+	}
+	for _, value := range values {
+		if err := EncodeProp(writer, prop.ArrayElement, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
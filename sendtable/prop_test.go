@@ -0,0 +1,179 @@
+package sendtable
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/galaco/bitbuf"
+)
+
+func TestDecodeProp_RoundTrip(t *testing.T) {
+	element := &Prop{Type: PropTypeInt, Name: "000", Flags: FlagUnsigned | FlagInsideArray, NumBits: 21}
+	testCases := []struct {
+		prop  *Prop
+		value interface{}
+	}{
+		{&Prop{Type: PropTypeInt, NumBits: 7}, int32(-64)},
+		{&Prop{Type: PropTypeInt, Flags: FlagUnsigned, NumBits: 32}, int32(-1)},
+		{&Prop{Type: PropTypeInt, Flags: FlagVarInt}, int32(-300000)},
+		{&Prop{Type: PropTypeInt, Flags: FlagVarInt | FlagUnsigned}, int32(300000)},
+		{&Prop{Type: PropTypeInt64, NumBits: 64}, int64(-1 << 40)},
+		{&Prop{Type: PropTypeInt64, Flags: FlagUnsigned, NumBits: 48}, int64(1<<47 + 12345)},
+		{&Prop{Type: PropTypeInt64, Flags: FlagVarInt}, int64(-1 << 50)},
+		{&Prop{Type: PropTypeInt64, Flags: FlagVarInt | FlagUnsigned}, int64(math.MaxInt64)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagNoScale}, float32(-1234.5678)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCoord}, float32(-512.25)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCoordMP}, float32(1024.5)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCoordMPLowPrecision}, float32(-8.5)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCoordMPIntegral}, float32(300)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCoordMP | FlagCoordMPIntegral}, float32(-300)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCoordMP | FlagCoordMPLowPrecision}, float32(20.125)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagNormal}, float32(-1)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCellCoord, NumBits: 9}, float32(200.25)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCellCoordLowPrecision, NumBits: 9}, float32(100.5)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCellCoordIntegral, NumBits: 9}, float32(511)},
+		{&Prop{Type: PropTypeFloat, Flags: FlagCellCoord | FlagCellCoordIntegral, NumBits: 9}, float32(300)},
+		{&Prop{Type: PropTypeFloat, LowValue: 0, HighValue: 1, NumBits: 2}, float32(2.0 / 3.0)},
+		{&Prop{Type: PropTypeFloat, LowValue: -10, HighValue: 10, NumBits: 1}, float32(10)},
+		{&Prop{Type: PropTypeVector, Flags: FlagCoord}, [3]float32{1, -2.5, 3}},
+		{&Prop{Type: PropTypeVector, Flags: FlagNormal}, [3]float32{0, 0, -1}},
+		{&Prop{Type: PropTypeVectorXY, Flags: FlagNoScale}, [2]float32{-7, 7}},
+		{&Prop{Type: PropTypeString}, "weapon_crowbar"},
+		{&Prop{Type: PropTypeString}, ""},
+		{&Prop{Type: PropTypeArray, NumElements: 48, ArrayElement: element}, []interface{}{int32(1), int32(2097151), int32(0)}},
+		{&Prop{Type: PropTypeArray, NumElements: 1, ArrayElement: element}, []interface{}{}},
+	}
+	for _, tc := range testCases {
+		writer := bitbuf.NewGrowableWriter(0, 0)
+		if err := EncodeProp(writer, tc.prop, tc.value); err != nil {
+			t.Fatalf("%s %v: %s", tc.prop.Type, tc.value, err)
+		}
+		reader := bitbuf.NewReader(writer.Data())
+		actual, err := DecodeProp(reader, tc.prop)
+		if err != nil {
+			t.Fatalf("%s %v: %s", tc.prop.Type, tc.value, err)
+		}
+		if !reflect.DeepEqual(actual, tc.value) {
+			t.Errorf("expected: %v, but received: %v", tc.value, actual)
+		}
+		if reader.BitsRead() != writer.BitsWritten() {
+			t.Errorf("%s. expected: %d bits read, but received: %d", tc.prop.Type, writer.BitsWritten(), reader.BitsRead())
+		}
+	}
+}
+
+func TestEncodeProp_CombinedCoordFlags(t *testing.T) {
+	testCases := []struct {
+		flags    Flags
+		expected func(writer *bitbuf.Writer) error
+	}{
+		{FlagCoordMP | FlagCoordMPIntegral, func(writer *bitbuf.Writer) error { return writer.WriteBitCoordMP(300, true, false) }},
+		{FlagCoordMP | FlagCoordMPLowPrecision, func(writer *bitbuf.Writer) error { return writer.WriteBitCoordMP(300, false, true) }},
+		{FlagCellCoord | FlagCellCoordIntegral, func(writer *bitbuf.Writer) error { return writer.WriteBitCellCoord(300, 9, true, false) }},
+		{FlagCellCoord | FlagCellCoordLowPrecision, func(writer *bitbuf.Writer) error { return writer.WriteBitCellCoord(300, 9, false, true) }},
+	}
+	for _, tc := range testCases {
+		expected := bitbuf.NewGrowableWriter(0, 0)
+		if err := tc.expected(expected); err != nil {
+			t.Fatal(err)
+		}
+		writer := bitbuf.NewGrowableWriter(0, 0)
+		if err := EncodeProp(writer, &Prop{Type: PropTypeFloat, Flags: tc.flags, NumBits: 9}, float32(300)); err != nil {
+			t.Fatal(err)
+		}
+		if writer.BitsWritten() != expected.BitsWritten() || !reflect.DeepEqual(writer.Data(), expected.Data()) {
+			t.Errorf("%b. expected: %v, but received: %v", tc.flags, expected.Data(), writer.Data())
+		}
+	}
+}
+
+func TestDecodeProp_Quantized(t *testing.T) {
+	prop := &Prop{Type: PropTypeFloat, LowValue: 0, HighValue: 360, NumBits: 10}
+	for value, expected := range map[float32]float32{
+		-5:  0,
+		0:   0,
+		360: 360,
+		400: 360,
+		// 90 is between steps 255 (89.736...) and 256 (90.088...), rounding up
+		90: float32(256) / 1023 * 360,
+	} {
+		writer := bitbuf.NewGrowableWriter(0, 0)
+		if err := EncodeProp(writer, prop, value); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := DecodeProp(bitbuf.NewReader(writer.Data()), prop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("%f. expected: %f, but received: %f", value, expected, actual)
+		}
+	}
+}
+
+func TestDecodeProp_VectorNormal(t *testing.T) {
+	prop := &Prop{Type: PropTypeVector, Flags: FlagNormal}
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	if err := EncodeProp(writer, prop, [3]float32{0.6, 0, -0.8}); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := DecodeProp(bitbuf.NewReader(writer.Data()), prop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vec := actual.([3]float32)
+	if math.Abs(float64(vec[0]-0.6)) > 0.001 || vec[1] != 0 || math.Abs(float64(vec[2]+0.8)) > 0.001 {
+		t.Errorf("expected: %v, but received: %v", [3]float32{0.6, 0, -0.8}, vec)
+	}
+	// 11 bits and a sign per component, plus the sign of z
+	if writer.BitsWritten() != 25 {
+		t.Errorf("expected: %d, but received: %d", 25, writer.BitsWritten())
+	}
+}
+
+func TestEncodeProp_Invalid(t *testing.T) {
+	element := &Prop{Type: PropTypeInt, NumBits: 8}
+	testCases := []struct {
+		prop  *Prop
+		value interface{}
+	}{
+		{&Prop{Type: PropTypeInt, NumBits: 8}, float32(1)},
+		{&Prop{Type: PropTypeFloat, NumBits: 8}, int32(1)},
+		{&Prop{Type: PropTypeDataTable}, nil},
+		{&Prop{Type: PropTypeFloat, NumBits: 0}, float32(1)},
+		{&Prop{Type: PropTypeInt64, NumBits: 32}, int64(1)},
+		{&Prop{Type: PropTypeString}, string(make([]byte, MaxStringLength+1))},
+		{&Prop{Type: PropTypeArray, NumElements: 1, ArrayElement: element}, []interface{}{int32(1), int32(2)}},
+		{&Prop{Type: PropTypeArray, NumElements: 1}, []interface{}{}},
+		{&Prop{Type: PropTypeArray, NumElements: 2, ArrayElement: element}, []interface{}{"1"}},
+	}
+	for _, tc := range testCases {
+		if err := EncodeProp(bitbuf.NewGrowableWriter(0, 0), tc.prop, tc.value); err == nil {
+			t.Errorf("%s %T. expected an error, but received none", tc.prop.Type, tc.value)
+		}
+	}
+}
+
+func TestDecodeProp_Invalid(t *testing.T) {
+	reader := bitbuf.NewReader(make([]byte, 16))
+	if _, err := DecodeProp(reader, &Prop{Type: PropTypeDataTable}); err == nil {
+		t.Error("expected an error, but received none")
+	}
+
+	// 3 elements, in an array of up to 2
+	writer := bitbuf.NewGrowableWriter(0, 0)
+	writer.WriteUnsignedBitInt32(3, 2)
+	prop := &Prop{Type: PropTypeArray, NumElements: 2, ArrayElement: &Prop{Type: PropTypeInt, NumBits: 1}}
+	if _, err := DecodeProp(bitbuf.NewReader(writer.Data()), prop); err == nil {
+		t.Error("expected an error, but received none")
+	}
+}
+
+func TestArrayCountBits(t *testing.T) {
+	for numElements, expected := range map[uint32]uint{0: 1, 1: 1, 2: 2, 3: 2, 48: 6, 64: 7, 1023: 10} {
+		if actual := arrayCountBits(&Prop{NumElements: numElements}); actual != expected {
+			t.Errorf("%d. expected: %d, but received: %d", numElements, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package sendtable parses the SendTables and server classes sent in dem_datatables
+// and svc_SendTable, flattens them into the prop order used to encode entities,
+// and decodes and encodes the values of their props.
+//
+// Only the Source 2013 (Orange Box) encoding is supported. CS:GO sends its tables as
+// protobuf CSVCMsg_SendTable messages instead, which this package does not parse.
+package sendtable
+
+import (
+	"fmt"
+)
+
+const (
+	// PropTypeBits is the width of a prop type
+	PropTypeBits = 5
+	// NumPropsBits is the width of a table's prop count
+	NumPropsBits = 10
+	// NumElementsBits is the width of an array prop's element count
+	NumElementsBits = 10
+	// NumBitsBits is the width of a prop's bit count
+	NumBitsBits = 7
+
+	// DefaultPriority is the priority of props that don't specify one
+	DefaultPriority = 128
+	// ChangesOftenPriority is the priority given to props flagged FlagChangesOften
+	ChangesOftenPriority = 64
+)
+
+// PropType is the type of a SendProp's value
+type PropType uint8
+
+// Prop types, as DPT_* in the engine
+const (
+	PropTypeInt PropType = iota
+	PropTypeFloat
+	PropTypeVector
+	PropTypeVectorXY
+	PropTypeString
+	PropTypeArray
+	PropTypeDataTable
+	PropTypeInt64
+)
+
+var propTypeNames = [...]string{
+	PropTypeInt:       "DPT_Int",
+	PropTypeFloat:     "DPT_Float",
+	PropTypeVector:    "DPT_Vector",
+	PropTypeVectorXY:  "DPT_VectorXY",
+	PropTypeString:    "DPT_String",
+	PropTypeArray:     "DPT_Array",
+	PropTypeDataTable: "DPT_DataTable",
+	PropTypeInt64:     "DPT_Int64",
+}
+
+func (propType PropType) String() string {
+	if int(propType) < len(propTypeNames) {
+		return propTypeNames[propType]
+	}
+	return fmt.Sprintf("PropType(%d)", uint8(propType))
+}
+
+// Flags are the SPROP_* flags of a prop.
+// Flags are sent in the Source 2013 (Orange Box) bit layout, which shares a bit between
+// SPROP_NORMAL and SPROP_VARINT, so are normalized onto this set when read and mapped back
+// when written. See NormalizeFlags.
+type Flags uint32
+
+// Prop flags, as SPROP_* in the engine
+const (
+	FlagUnsigned Flags = 1 << iota
+	FlagCoord
+	FlagNoScale
+	FlagRoundDown
+	FlagRoundUp
+	FlagNormal
+	FlagExclude
+	FlagXYZE
+	FlagInsideArray
+	FlagProxyAlwaysYes
+	FlagIsAVectorElem
+	FlagCollapsible
+	FlagCoordMP
+	FlagCoordMPLowPrecision
+	FlagCoordMPIntegral
+	// The cell coord flags are not sent by Source 2013, but are honoured by DecodeProp
+	// and EncodeProp for props constructed directly.
+	FlagCellCoord
+	FlagCellCoordLowPrecision
+	FlagCellCoordIntegral
+	FlagChangesOften
+	FlagVarInt
+)
+
+// wireFlags maps each flag bit as sent onto Flags
+var wireFlags = [...]Flags{
+	FlagUnsigned,
+	FlagCoord,
+	FlagNoScale,
+	FlagRoundDown,
+	FlagRoundUp,
+	FlagNormal,
+	FlagExclude,
+	FlagXYZE,
+	FlagInsideArray,
+	FlagProxyAlwaysYes,
+	FlagChangesOften,
+	FlagIsAVectorElem,
+	FlagCollapsible,
+	FlagCoordMP,
+	FlagCoordMPLowPrecision,
+	FlagCoordMPIntegral,
+}
+
+// FlagBits is the number of flag bits sent for each prop
+const FlagBits = uint(len(wireFlags))
+
+// NormalizeFlags maps flags as sent for a prop of propType onto Flags
+func NormalizeFlags(raw uint32, propType PropType) Flags {
+	var flags Flags
+	for bit, flag := range wireFlags {
+		if raw&(1<<uint(bit)) != 0 {
+			flags |= flag
+		}
+	}
+	if flags&FlagNormal != 0 && (propType == PropTypeInt || propType == PropTypeInt64) {
+		flags = flags&^FlagNormal | FlagVarInt
+	}
+	return flags
+}
+
+// RawFlags maps flags of a prop of propType back to the flags as sent.
+// The cell coord flags can't be sent, so are dropped.
+func RawFlags(flags Flags, propType PropType) uint32 {
+	if flags&FlagVarInt != 0 && (propType == PropTypeInt || propType == PropTypeInt64) {
+		flags |= FlagNormal
+	}
+	var raw uint32
+	for bit, flag := range wireFlags {
+		if flags&flag != 0 {
+			raw |= 1 << uint(bit)
+		}
+	}
+	return raw
+}
+
+// Prop is a single SendProp
+type Prop struct {
+	Type  PropType
+	Name  string
+	Flags Flags
+	// Priority orders props when flattening. Lower priorities are sent first.
+	Priority int
+	// DataTableName is the table referenced by a data table prop, or the table
+	// containing the excluded prop for a prop flagged FlagExclude
+	DataTableName string
+	// NumElements is the number of elements of an array prop
+	NumElements uint32
+	LowValue    float32
+	HighValue   float32
+	NumBits     uint32
+	// ArrayElement describes the elements of an array prop. It is the prop preceding
+	// the array in its table, which is flagged FlagInsideArray.
+	ArrayElement *Prop
+}
+
+// Table is a single SendTable
+type Table struct {
+	Name         string
+	NeedsDecoder bool
+	Props        []*Prop
+}
+
+// FlatProp is a prop in the flattened order, along with the table it was declared in
+type FlatProp struct {
+	Prop  *Prop
+	Table *Table
+}
+
+// Name returns the prop name qualified by its table name, e.g. DT_BaseEntity.m_vecOrigin
+func (prop FlatProp) Name() string {
+	return prop.Table.Name + "." + prop.Prop.Name
+}
+
+// ServerClass is an entity class, and the flattened props used to encode it
+type ServerClass struct {
+	ID            uint16
+	Name          string
+	DataTableName string
+	// Props are the flattened props of the class's data table. Entity updates refer to props by index into Props.
+	Props []FlatProp
+
+	propIndices map[string]int
+}
+
+// PropIndex returns the index of a prop in Props.
+// name is either qualified by its table name, or unqualified, in which case the first prop with that name is found.
+func (class *ServerClass) PropIndex(name string) (int, bool) {
+	if class.propIndices == nil {
+		class.indexProps()
+	}
+	index, ok := class.propIndices[name]
+	return index, ok
+}
+
+// indexProps builds the lookup used by PropIndex
+func (class *ServerClass) indexProps() {
+	class.propIndices = make(map[string]int, len(class.Props)*2)
+	for i := len(class.Props) - 1; i >= 0; i-- {
+		class.propIndices[class.Props[i].Prop.Name] = i
+	}
+	for i, prop := range class.Props {
+		class.propIndices[prop.Name()] = i
+	}
+}
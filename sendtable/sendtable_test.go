@@ -0,0 +1,64 @@
+package sendtable
+
+import (
+	"testing"
+)
+
+func TestNormalizeFlags(t *testing.T) {
+	testCases := []struct {
+		raw      uint32
+		propType PropType
+		expected Flags
+	}{
+		{1<<0 | 1<<10, PropTypeFloat, FlagUnsigned | FlagChangesOften},
+		{1<<11 | 1<<12, PropTypeVector, FlagIsAVectorElem | FlagCollapsible},
+		{1<<13 | 1<<15, PropTypeFloat, FlagCoordMP | FlagCoordMPIntegral},
+		{1 << 5, PropTypeVector, FlagNormal},
+		{1 << 5, PropTypeInt, FlagVarInt},
+	}
+	for _, tc := range testCases {
+		actual := NormalizeFlags(tc.raw, tc.propType)
+		if actual != tc.expected {
+			t.Errorf("expected: %b, but received: %b", tc.expected, actual)
+		}
+		if raw := RawFlags(actual, tc.propType); raw != tc.raw {
+			t.Errorf("expected: %b, but received: %b", tc.raw, raw)
+		}
+	}
+}
+
+func TestRawFlags_Unrepresentable(t *testing.T) {
+	if raw := RawFlags(FlagCellCoord|FlagUnsigned, PropTypeFloat); raw != 1 {
+		t.Errorf("expected: %b, but received: %b", 1, raw)
+	}
+}
+
+func TestPropType_String(t *testing.T) {
+	if PropTypeVectorXY.String() != "DPT_VectorXY" {
+		t.Errorf("expected: %s, but received: %s", "DPT_VectorXY", PropTypeVectorXY)
+	}
+	if PropType(20).String() != "PropType(20)" {
+		t.Errorf("expected: %s, but received: %s", "PropType(20)", PropType(20))
+	}
+}
+
+func TestServerClass_PropIndex(t *testing.T) {
+	dt, err := NewDataTables(syntheticTables(), syntheticClasses())
+	if err != nil {
+		t.Fatal(err)
+	}
+	class := dt.Class(1)
+	for name, expected := range map[string]int{
+		"m_vecOrigin":                      0,
+		"DT_BasePlayer.m_flSimulationTime": 1,
+		"m_flFOVTime":                      5,
+	} {
+		index, ok := class.PropIndex(name)
+		if !ok || index != expected {
+			t.Errorf("%s. expected: %d, but received: %d", name, expected, index)
+		}
+	}
+	if _, ok := class.PropIndex("m_nModelIndex"); ok {
+		t.Error("expected excluded prop not to be found")
+	}
+}
@@ -0,0 +1,176 @@
+package bitbuf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// StopReason describes why a delimited string read finished
+type StopReason int
+
+const (
+	// StopDelimiter means the delimiter was found. It is consumed, but not included in the string.
+	StopDelimiter StopReason = iota
+	// StopMaxLength means the maximum length was read without finding the delimiter
+	StopMaxLength
+	// StopEndOfBuffer means the buffer ran out of whole bytes without finding the delimiter
+	StopEndOfBuffer
+)
+
+func (reason StopReason) String() string {
+	switch reason {
+	case StopDelimiter:
+		return "delimiter"
+	case StopMaxLength:
+		return "max length"
+	case StopEndOfBuffer:
+		return "end of buffer"
+	}
+	return fmt.Sprintf("StopReason(%d)", int(reason))
+}
+
+// ReadCString reads a null-terminated string, consuming the terminator.
+// If maxLength != 0 and maxLength bytes are read without finding a terminator,
+// they are returned along with ErrStringTruncated.
+// Unlike ReadString, reaching the end of the buffer before a terminator is an error.
+func (buf *Reader) ReadCString(maxLength uint) (string, error) {
+	if maxLength == 0 {
+		maxLength = math.MaxUint32
+	}
+
+	retVal := make([]byte, 0)
+	for i := uint(0); i < maxLength; i++ {
+		val, err := buf.ReadByte()
+		if err != nil {
+			return string(retVal), err
+		}
+		if val == 0 {
+			return string(retVal), nil
+		}
+		retVal = append(retVal, val)
+	}
+	return string(retVal), ErrStringTruncated
+}
+
+// ReadFixedString reads a string stored in exactly length bytes, as written by WriteFixedString.
+// The string ends at the first null byte, if any.
+func (buf *Reader) ReadFixedString(length uint) (string, error) {
+	raw, err := buf.ReadBytes(length)
+	if err != nil {
+		return "", err
+	}
+	if end := bytes.IndexByte(raw, 0); end != -1 {
+		raw = raw[:end]
+	}
+	return string(raw), nil
+}
+
+// ReadLenPrefixedString reads a string prefixed by its length in lenBits bits,
+// as written by WriteLenPrefixedString.
+func (buf *Reader) ReadLenPrefixedString(lenBits uint) (string, error) {
+	length, err := buf.ReadUint32Bits(lenBits)
+	if err != nil {
+		return "", err
+	}
+	raw, err := buf.ReadBytes(uint(length))
+	return string(raw), err
+}
+
+// ReadLine reads a string until a newline or null terminator, which is consumed but not returned.
+// If maxLength != 0 at most maxLength bytes are read. The StopReason reports which limit was hit.
+func (buf *Reader) ReadLine(maxLength uint) (string, StopReason, error) {
+	return buf.readUntil(func(b byte) bool {
+		return b == '\n' || b == 0
+	}, maxLength)
+}
+
+// ReadStringUntil reads a string until delim, which is consumed but not returned.
+// If maxLength != 0 at most maxLength bytes are read. The StopReason reports which limit was hit.
+func (buf *Reader) ReadStringUntil(delim byte, maxLength uint) (string, StopReason, error) {
+	return buf.readUntil(func(b byte) bool {
+		return b == delim
+	}, maxLength)
+}
+
+// readUntil reads bytes until isDelim matches one, maxLength bytes are read, or the buffer runs out
+func (buf *Reader) readUntil(isDelim func(byte) bool, maxLength uint) (string, StopReason, error) {
+	retVal := make([]byte, 0)
+	for maxLength == 0 || uint(len(retVal)) < maxLength {
+		if buf.source == nil && buf.totalBits-buf.currentBit < 8 {
+			return string(retVal), StopEndOfBuffer, nil
+		}
+		val, err := buf.ReadByte()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return string(retVal), StopEndOfBuffer, nil
+		}
+		if err != nil {
+			return string(retVal), StopEndOfBuffer, err
+		}
+		if isDelim(val) {
+			return string(retVal), StopDelimiter, nil
+		}
+		retVal = append(retVal, val)
+	}
+	return string(retVal), StopMaxLength, nil
+}
+
+// WriteCString writes a string followed by a null terminator, as read by ReadCString
+// and ReadString. A string containing a null byte will be cut short when read.
+func (writer *Writer) WriteCString(val string) error {
+	if err := writer.WriteString(val); err != nil {
+		return err
+	}
+	return writer.WriteByte(0)
+}
+
+// WriteFixedString writes a string in exactly length bytes, padding it with null bytes
+// if it is shorter. A longer string is truncated, and ErrStringTruncated returned
+// once the truncated string has been written.
+func (writer *Writer) WriteFixedString(val string, length uint) error {
+	var truncated error
+	if uint(len(val)) > length {
+		val = val[:length]
+		truncated = ErrStringTruncated
+	}
+	if err := writer.ensureInBounds(length << 3); err != nil {
+		writer.currentBit = writer.totalBits
+		return err
+	}
+	if err := writer.WriteString(val); err != nil {
+		return err
+	}
+	for i := uint(len(val)); i < length; i++ {
+		if err := writer.WriteByte(0); err != nil {
+			return err
+		}
+	}
+	return truncated
+}
+
+// WriteLenPrefixedString writes the length of a string in lenBits bits, followed by
+// the string itself. A string too long for its length to fit in lenBits is truncated,
+// and ErrStringTruncated returned once the truncated string has been written.
+func (writer *Writer) WriteLenPrefixedString(val string, lenBits uint) error {
+	if lenBits > 32 {
+		return fmt.Errorf("%w: cannot handle a string length of more than 32 bits", ErrWidthTooLarge)
+	}
+	var truncated error
+	if maxLength := uint64(1)<<lenBits - 1; uint64(len(val)) > maxLength {
+		val = val[:maxLength]
+		truncated = ErrStringTruncated
+	}
+	if err := writer.ensureInBounds(lenBits + uint(len(val))<<3); err != nil {
+		writer.currentBit = writer.totalBits
+		return err
+	}
+	if err := writer.WriteUnsignedBitInt32(uint32(len(val)), lenBits); err != nil {
+		return err
+	}
+	if err := writer.WriteString(val); err != nil {
+		return err
+	}
+	return truncated
+}
@@ -0,0 +1,167 @@
+package bitbuf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriter_WriteCString(t *testing.T) {
+	sut := NewGrowableWriter(0, 0)
+	sut.WriteUnsignedBitInt32(5, 3)
+	if err := sut.WriteCString("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteCString(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteCString("world"); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := NewReader(sut.Data())
+	reader.Seek(3)
+	if val, err := reader.ReadCString(0); err != nil || val != "hello" {
+		t.Errorf("expected: %s, but received: %s (%v)", "hello", val, err)
+	}
+	if val, err := reader.ReadString(0); err != nil || val != "" {
+		t.Errorf("expected: %s, but received: %s (%v)", "", val, err)
+	}
+	if val, err := reader.ReadCString(3); !errors.Is(err, ErrStringTruncated) || val != "wor" {
+		t.Errorf("expected: %s, but received: %s (%v)", "wor", val, err)
+	}
+	if val, err := reader.ReadCString(0); err != nil || val != "ld" {
+		t.Errorf("expected: %s, but received: %s (%v)", "ld", val, err)
+	}
+}
+
+func TestReader_ReadCString_Unterminated(t *testing.T) {
+	sut := NewReader([]byte("abc"))
+	if val, err := sut.ReadCString(0); !errors.Is(err, ErrOutOfBounds) || val != "abc" {
+		t.Errorf("expected: %s, but received: %s (%v)", "abc", val, err)
+	}
+}
+
+func TestWriter_WriteFixedString(t *testing.T) {
+	sut := NewWriter(10)
+	sut.WriteOneBit(true)
+	if err := sut.WriteFixedString("abc", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteFixedString("abcdefgh", 4); !errors.Is(err, ErrStringTruncated) {
+		t.Errorf("expected: %v, but received: %v", ErrStringTruncated, err)
+	}
+	if sut.BitsWritten() != 73 {
+		t.Errorf("expected: %d, but received: %d", 73, sut.BitsWritten())
+	}
+	if err := sut.WriteFixedString("abc", 8); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+
+	reader := NewReader(sut.Data())
+	reader.Seek(1)
+	if val, err := reader.ReadFixedString(5); err != nil || val != "abc" {
+		t.Errorf("expected: %s, but received: %s (%v)", "abc", val, err)
+	}
+	if val, err := reader.ReadFixedString(4); err != nil || val != "abcd" {
+		t.Errorf("expected: %s, but received: %s (%v)", "abcd", val, err)
+	}
+}
+
+func TestWriter_WriteLenPrefixedString(t *testing.T) {
+	sut := NewGrowableWriter(0, 0)
+	if err := sut.WriteLenPrefixedString("hello", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteLenPrefixedString("overflowing", 3); !errors.Is(err, ErrStringTruncated) {
+		t.Errorf("expected: %v, but received: %v", ErrStringTruncated, err)
+	}
+	if err := sut.WriteLenPrefixedString("", 9); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteLenPrefixedString("x", 33); !errors.Is(err, ErrWidthTooLarge) {
+		t.Errorf("expected: %v, but received: %v", ErrWidthTooLarge, err)
+	}
+	if sut.BitsWritten() != 5+40+3+56+9 {
+		t.Errorf("expected: %d, but received: %d", 5+40+3+56+9, sut.BitsWritten())
+	}
+
+	reader := NewReader(sut.Data())
+	expected := []string{"hello", "overflo", ""}
+	for i, lenBits := range []uint{5, 3, 9} {
+		if val, err := reader.ReadLenPrefixedString(lenBits); err != nil || val != expected[i] {
+			t.Errorf("expected: %s, but received: %s (%v)", expected[i], val, err)
+		}
+	}
+}
+
+func TestReader_ReadLine(t *testing.T) {
+	writer := NewGrowableWriter(0, 0)
+	writer.WriteUnsignedBitInt32(3, 5)
+	writer.WriteString("echo hi\nconnect\x00status\nlongline\nend")
+	writer.WriteUnsignedBitInt32(1, 3)
+
+	sut := NewReader(writer.Data())
+	sut.Seek(5)
+	cases := []struct {
+		maxLength uint
+		expected  string
+		reason    StopReason
+	}{
+		{0, "echo hi", StopDelimiter},
+		{0, "connect", StopDelimiter},
+		{6, "status", StopMaxLength},
+		{0, "", StopDelimiter},
+		{8, "longline", StopMaxLength},
+		{0, "", StopDelimiter},
+		{0, "end", StopEndOfBuffer},
+		{0, "", StopEndOfBuffer},
+	}
+	for _, c := range cases {
+		val, reason, err := sut.ReadLine(c.maxLength)
+		if err != nil || val != c.expected || reason != c.reason {
+			t.Errorf("expected: %q (%s), but received: %q (%s, %v)", c.expected, c.reason, val, reason, err)
+		}
+	}
+	if sut.BitsRead() != sut.Size()-3 {
+		t.Errorf("expected: %d, but received: %d", sut.Size()-3, sut.BitsRead())
+	}
+}
+
+func TestReader_ReadStringUntil(t *testing.T) {
+	data := []byte("key=value;name=\x00x;")
+	for _, stream := range []bool{false, true} {
+		sut := NewReader(data)
+		if stream {
+			sut = NewStreamReader(iotest.OneByteReader(bytes.NewReader(data)))
+		}
+		cases := []struct {
+			delim    byte
+			expected string
+			reason   StopReason
+		}{
+			{'=', "key", StopDelimiter},
+			{';', "value", StopDelimiter},
+			{'=', "name", StopDelimiter},
+			{';', "\x00x", StopDelimiter},
+			{';', "", StopEndOfBuffer},
+		}
+		for _, c := range cases {
+			val, reason, err := sut.ReadStringUntil(c.delim, 0)
+			if err != nil || val != c.expected || reason != c.reason {
+				t.Errorf("expected: %q (%s), but received: %q (%s, %v)", c.expected, c.reason, val, reason, err)
+			}
+		}
+	}
+}
+
+func TestReader_ReadStringUntil_StickyError(t *testing.T) {
+	sut := NewReader([]byte("abc"))
+	sut.SetStickyErrors(true)
+	sut.ReadUint32()
+
+	if _, _, err := sut.ReadStringUntil(';', 0); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+}
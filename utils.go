@@ -1,34 +1,8 @@
 package bitbuf
 
-import (
-	"bytes"
-	"encoding/binary"
-)
-
-func bytesToUint32(data []byte) (ret uint32, err error) {
-	buf := bytes.NewBuffer(data)
-	err = binary.Read(buf, binary.LittleEndian, &ret)
-
-	return ret, err
-}
-
-func bytesToInt64(data []byte) (ret int64, err error) {
-	buf := bytes.NewBuffer(data)
-	err = binary.Read(buf, binary.LittleEndian, &ret)
-
-	return ret, err
-}
-
-func bytesToFloat32(data []byte) (ret float32, err error) {
-	buf := bytes.NewBuffer(data)
-	err = binary.Read(buf, binary.LittleEndian, &ret)
-
-	return ret, err
-}
-
-func bytesToFloat64(data []byte) (ret float64, err error) {
-	buf := bytes.NewBuffer(data)
-	err = binary.Read(buf, binary.LittleEndian, &ret)
-
-	return ret, err
+func absInt32(value int32) int32 {
+	if value < 0 {
+		return -value
+	}
+	return value
 }
@@ -0,0 +1,129 @@
+package bitbuf
+
+const (
+	// MaxVarInt32Bytes is the maximum number of bytes a valid 32bit varint can occupy
+	MaxVarInt32Bytes = 5
+	// MaxVarInt64Bytes is the maximum number of bytes a valid 64bit varint can occupy
+	MaxVarInt64Bytes = 10
+)
+
+// ReadUBitVar reads an unsigned integer with a variable bit length.
+// A 2 bit prefix selects whether the value is stored in 4, 8, 12 or 32 bits.
+func (buf *Reader) ReadUBitVar() (uint32, error) {
+	encoding, err := buf.ReadUint32Bits(2)
+	if err != nil {
+		return 0, err
+	}
+	switch encoding {
+	case 0:
+		return buf.ReadUint32Bits(4)
+	case 1:
+		return buf.ReadUint32Bits(8)
+	case 2:
+		return buf.ReadUint32Bits(12)
+	default:
+		return buf.ReadUint32Bits(32)
+	}
+}
+
+// ReadVarInt32 reads a protobuf style base 128 varint of up to 32 bits.
+// The varint does not need to be byte aligned.
+func (buf *Reader) ReadVarInt32() (uint32, error) {
+	result := uint32(0)
+	for count := uint(0); count < MaxVarInt32Bytes; count++ {
+		b, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return 0, err
+		}
+		result |= (b & 0x7F) << (7 * count)
+		if b&0x80 == 0 {
+			return result, nil
+		}
+	}
+	return 0, ErrMalformedVarInt
+}
+
+// ReadVarInt64 reads a protobuf style base 128 varint of up to 64 bits.
+// The varint does not need to be byte aligned.
+func (buf *Reader) ReadVarInt64() (uint64, error) {
+	result := uint64(0)
+	for count := uint(0); count < MaxVarInt64Bytes; count++ {
+		b, err := buf.ReadUint32Bits(8)
+		if err != nil {
+			return 0, err
+		}
+		result |= uint64(b&0x7F) << (7 * count)
+		if b&0x80 == 0 {
+			return result, nil
+		}
+	}
+	return 0, ErrMalformedVarInt
+}
+
+// ReadSignedVarInt32 reads a zigzag encoded varint of up to 32 bits
+func (buf *Reader) ReadSignedVarInt32() (int32, error) {
+	v, err := buf.ReadVarInt32()
+	if err != nil {
+		return 0, err
+	}
+	return int32(v>>1) ^ -int32(v&1), nil
+}
+
+// ReadSignedVarInt64 reads a zigzag encoded varint of up to 64 bits
+func (buf *Reader) ReadSignedVarInt64() (int64, error) {
+	v, err := buf.ReadVarInt64()
+	if err != nil {
+		return 0, err
+	}
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+// WriteUBitVar writes an unsigned integer with a variable bit length.
+// A 2 bit prefix selects whether the value is stored in 4, 8, 12 or 32 bits.
+func (writer *Writer) WriteUBitVar(data uint32) error {
+	encoding, numBits := uint32(3), uint(32)
+	switch {
+	case data < 0x10:
+		encoding, numBits = 0, 4
+	case data < 0x100:
+		encoding, numBits = 1, 8
+	case data < 0x1000:
+		encoding, numBits = 2, 12
+	}
+	if err := writer.WriteUnsignedBitInt32(encoding, 2); err != nil {
+		return err
+	}
+	return writer.WriteUnsignedBitInt32(data, numBits)
+}
+
+// WriteVarInt32 writes a protobuf style base 128 varint of up to 32 bits
+func (writer *Writer) WriteVarInt32(data uint32) error {
+	for data > 0x7F {
+		if err := writer.WriteUnsignedBitInt32((data&0x7F)|0x80, 8); err != nil {
+			return err
+		}
+		data >>= 7
+	}
+	return writer.WriteUnsignedBitInt32(data, 8)
+}
+
+// WriteVarInt64 writes a protobuf style base 128 varint of up to 64 bits
+func (writer *Writer) WriteVarInt64(data uint64) error {
+	for data > 0x7F {
+		if err := writer.WriteUnsignedBitInt32(uint32(data&0x7F)|0x80, 8); err != nil {
+			return err
+		}
+		data >>= 7
+	}
+	return writer.WriteUnsignedBitInt32(uint32(data), 8)
+}
+
+// WriteSignedVarInt32 writes a zigzag encoded varint of up to 32 bits
+func (writer *Writer) WriteSignedVarInt32(data int32) error {
+	return writer.WriteVarInt32(uint32(data<<1) ^ uint32(data>>31))
+}
+
+// WriteSignedVarInt64 writes a zigzag encoded varint of up to 64 bits
+func (writer *Writer) WriteSignedVarInt64(data int64) error {
+	return writer.WriteVarInt64(uint64(data<<1) ^ uint64(data>>63))
+}
@@ -0,0 +1,137 @@
+package bitbuf
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestReader_ReadUBitVar(t *testing.T) {
+	values := []uint32{0, 15, 16, 255, 256, 4095, 4096, math.MaxUint32}
+	expectedBits := []uint{6, 6, 10, 10, 14, 14, 34, 34}
+
+	for i, v := range values {
+		sut := NewWriter(8)
+		if err := sut.WriteUBitVar(v); err != nil {
+			t.Error(err)
+		}
+		if sut.BitsWritten() != expectedBits[i] {
+			t.Errorf("expected: %d bits written, but received: %d", expectedBits[i], sut.BitsWritten())
+		}
+		if val, err := NewReader(sut.Data()).ReadUBitVar(); err != nil || val != v {
+			t.Errorf("expected: %d, but received: %d (%v)", v, val, err)
+		}
+	}
+}
+
+func TestReader_ReadVarInt32(t *testing.T) {
+	values := []uint32{0, 1, 127, 128, 300, 16384, math.MaxUint32}
+
+	// Every starting bit offset within a byte must decode identically
+	for offset := uint(0); offset < 8; offset++ {
+		sut := NewWriter(64)
+		if err := sut.Seek(offset); err != nil {
+			t.Error(err)
+		}
+		for _, v := range values {
+			if err := sut.WriteVarInt32(v); err != nil {
+				t.Error(err)
+			}
+		}
+
+		reader := NewReader(sut.Data())
+		reader.Seek(int(offset))
+		for _, expected := range values {
+			if val, err := reader.ReadVarInt32(); err != nil || val != expected {
+				t.Errorf("expected: %d, but received: %d (%v)", expected, val, err)
+			}
+		}
+	}
+}
+
+func TestReader_ReadVarInt64(t *testing.T) {
+	values := []uint64{0, 1, 127, 128, 300, math.MaxUint32 + 1, math.MaxUint64}
+
+	for offset := uint(0); offset < 8; offset++ {
+		sut := NewWriter(128)
+		if err := sut.Seek(offset); err != nil {
+			t.Error(err)
+		}
+		for _, v := range values {
+			if err := sut.WriteVarInt64(v); err != nil {
+				t.Error(err)
+			}
+		}
+
+		reader := NewReader(sut.Data())
+		reader.Seek(int(offset))
+		for _, expected := range values {
+			if val, err := reader.ReadVarInt64(); err != nil || val != expected {
+				t.Errorf("expected: %d, but received: %d (%v)", expected, val, err)
+			}
+		}
+	}
+}
+
+func TestReader_ReadVarInt_MatchesEncodingBinary(t *testing.T) {
+	raw := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(raw, 1234567890123)
+
+	if val, err := NewReader(raw[:n]).ReadVarInt64(); err != nil || val != 1234567890123 {
+		t.Errorf("expected: %d, but received: %d (%v)", 1234567890123, val, err)
+	}
+}
+
+func TestReader_ReadVarInt_Malformed(t *testing.T) {
+	data := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}
+
+	if _, err := NewReader(data).ReadVarInt32(); !errors.Is(err, ErrMalformedVarInt) {
+		t.Errorf("expected: %v, but received: %v", ErrMalformedVarInt, err)
+	}
+	if _, err := NewReader(data).ReadVarInt64(); !errors.Is(err, ErrMalformedVarInt) {
+		t.Errorf("expected: %v, but received: %v", ErrMalformedVarInt, err)
+	}
+	if _, err := NewReader(data[:3]).ReadVarInt32(); err == nil {
+		t.Error("expected truncated varint to fail")
+	}
+}
+
+func TestReader_ReadSignedVarInt32(t *testing.T) {
+	values := []int32{0, -1, 1, -64, 64, math.MinInt32, math.MaxInt32}
+	sut := NewWriter(64)
+	for _, v := range values {
+		if err := sut.WriteSignedVarInt32(v); err != nil {
+			t.Error(err)
+		}
+	}
+
+	reader := NewReader(sut.Data())
+	for _, expected := range values {
+		if val, err := reader.ReadSignedVarInt32(); err != nil || val != expected {
+			t.Errorf("expected: %d, but received: %d (%v)", expected, val, err)
+		}
+	}
+
+	// zigzag encoding keeps small negative numbers small
+	if val, _ := NewReader([]byte{1}).ReadSignedVarInt32(); val != -1 {
+		t.Errorf("expected: %d, but received: %d", -1, val)
+	}
+}
+
+func TestReader_ReadSignedVarInt64(t *testing.T) {
+	values := []int64{0, -1, 1, -64, 64, math.MinInt64, math.MaxInt64}
+	sut := NewWriter(128)
+	for _, v := range values {
+		if err := sut.WriteSignedVarInt64(v); err != nil {
+			t.Error(err)
+		}
+	}
+
+	reader := NewReader(sut.Data())
+	for _, expected := range values {
+		if val, err := reader.ReadSignedVarInt64(); err != nil || val != expected {
+			t.Errorf("expected: %d, but received: %d (%v)", expected, val, err)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package bitbuf
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"unsafe"
 )
@@ -12,14 +14,31 @@ type Writer struct {
 	totalBits      uint
 	currentBit     uint
 	bitsWritten    uint
+
+	// growable writers reallocate internalBuffer as required, up to maxBits.
+	// A maxBits of 0 means the buffer may grow without limit.
+	growable bool
+	maxBits  uint
+
+	// sink is only set for writers created with NewStreamWriter.
+	// internalBuffer then holds the unflushed data, which begins
+	// bufferOffset bytes into the stream.
+	sink         io.Writer
+	bufferOffset uint
+
+	// sticky writers latch the first bounds failure in err, and refuse
+	// all further writes.
+	sticky bool
+	err    error
 }
 
 // Data returns the current written buffer
+// For a stream Writer only data that has not yet been flushed is returned.
 func (writer *Writer) Data() []byte {
 	if writer.BytesWritten() == 0 {
 		return make([]byte, 0)
 	}
-	return writer.internalBuffer[:writer.BytesWritten()]
+	return writer.internalBuffer[:uint(writer.BytesWritten())-writer.bufferOffset]
 }
 
 // BitsWritten returns number of bits written
@@ -34,11 +53,132 @@ func (writer *Writer) BytesWritten() int {
 
 // Seek sets the current writer position to the given location.
 // Seek index is in bits, NOT bytes!
-func (writer *Writer) Seek(position uint) {
-	if writer.totalBits > position {
-		writer.currentBit = writer.totalBits
+// A growable Writer expands to hold the new position. Otherwise seeking beyond
+// the end of the buffer returns a *BoundsError, and leaves the cursor in place.
+// A stream Writer cannot seek behind data it has already flushed, and will
+// return a *FlushedSeekError instead.
+func (writer *Writer) Seek(position uint) error {
+	if position < writer.bufferOffset<<3 {
+		return &FlushedSeekError{
+			Position: position,
+			Flushed:  writer.bufferOffset << 3,
+		}
+	}
+	if position > writer.currentBit {
+		if err := writer.ensureAvailable("seek", position-writer.currentBit); err != nil {
+			return err
+		}
 	}
 	writer.currentBit = position
+	return nil
+}
+
+// SeekBits sets the cursor to offset bits, interpreted according to whence in the
+// same way as io.Seeker: relative to the start, the cursor, or the end of the data
+// written so far. It returns the new position in bits.
+func (writer *Writer) SeekBits(offset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = offset
+	case io.SeekCurrent:
+		target = int64(writer.currentBit) + offset
+	case io.SeekEnd:
+		target = int64(writer.bitsWritten) + offset
+	default:
+		return int64(writer.currentBit), fmt.Errorf("bitbuf invalid whence %d", whence)
+	}
+
+	if target < 0 {
+		return int64(writer.currentBit), fmt.Errorf("%w: cannot seek to negative bit %d", ErrOutOfBounds, target)
+	}
+	if err := writer.Seek(uint(target)); err != nil {
+		return int64(writer.currentBit), err
+	}
+	return target, nil
+}
+
+// SkipBits advances the cursor by numBits, leaving the bits passed over unchanged.
+// They are counted as written, so skipping can be used to reserve space that is
+// filled in later.
+func (writer *Writer) SkipBits(numBits uint) error {
+	// checked here, as the target position may not be representable
+	if err := writer.ensureAvailable("seek", numBits); err != nil {
+		return err
+	}
+	if err := writer.Seek(writer.currentBit + numBits); err != nil {
+		return err
+	}
+	if writer.currentBit > writer.bitsWritten {
+		writer.bitsWritten = writer.currentBit
+	}
+	return writer.flushCompleted()
+}
+
+// SkipBytes advances the cursor by numBytes, as SkipBits.
+func (writer *Writer) SkipBytes(numBytes uint) error {
+	return writer.SkipBits(numBytes << 3)
+}
+
+// IsByteAligned returns whether the cursor is on a byte boundary
+func (writer *Writer) IsByteAligned() bool {
+	return writer.currentBit&7 == 0
+}
+
+// BitsToNextByte returns the number of bits between the cursor and the next byte boundary
+func (writer *Writer) BitsToNextByte() uint {
+	return -writer.currentBit & 7
+}
+
+// AlignToByte pads to the next byte boundary, if the cursor is not already on one.
+// The padding bits are all set to padBit.
+func (writer *Writer) AlignToByte(padBit bool) error {
+	numBits := writer.BitsToNextByte()
+	padding := uint32(0)
+	if padBit {
+		padding = 1<<numBits - 1
+	}
+	return writer.WriteUnsignedBitInt32(padding, numBits)
+}
+
+// SetStickyErrors enables or disables sticky error mode.
+// In sticky mode the first out of bounds write, or sink error of a stream Writer, is latched,
+// and every subsequent write or Flush does nothing and returns that same error. This allows a sequence of writes
+// to be checked once at the end via Err or IsOverflowed.
+func (writer *Writer) SetStickyErrors(sticky bool) {
+	writer.sticky = sticky
+}
+
+// Err returns the error latched in sticky mode, if any.
+// For a stream Writer this includes any error returned by the sink.
+func (writer *Writer) Err() error {
+	return writer.err
+}
+
+// IsOverflowed returns whether a write beyond the end of the buffer has been latched in sticky mode.
+func (writer *Writer) IsOverflowed() bool {
+	return errors.Is(writer.err, ErrOutOfBounds)
+}
+
+// Flush writes all buffered data to the sink of a stream Writer.
+// A partially written final byte is padded with 0 bits, and writing resumes
+// at the following byte boundary. Flush does nothing for other writers.
+func (writer *Writer) Flush() error {
+	if writer.sink == nil {
+		return nil
+	}
+	if writer.err != nil {
+		return writer.err
+	}
+	end := writer.bitsWritten
+	if writer.currentBit > end {
+		end = writer.currentBit
+	}
+	end = (end + 7) &^ 7
+	writer.bitsWritten = end
+	writer.currentBit = end
+
+	return writer.flushBytes(end>>3 - writer.bufferOffset)
 }
 
 // WriteByte writes a single byte
@@ -48,6 +188,26 @@ func (writer *Writer) WriteByte(val byte) error {
 
 // WriteBytes writes a byte slice
 func (writer *Writer) WriteBytes(val []byte) error {
+	// bytes can be copied directly when the cursor is aligned
+	if writer.IsByteAligned() {
+		if err := writer.ensureInBounds(uint(len(val)) << 3); err != nil {
+			writer.currentBit = writer.totalBits
+			return err
+		}
+		copy(writer.internalBuffer[writer.currentBit>>3-writer.bufferOffset:], val)
+		writer.currentBit += uint(len(val)) << 3
+		if writer.currentBit > writer.bitsWritten {
+			writer.bitsWritten = writer.currentBit
+		}
+		return writer.flushCompleted()
+	}
+
+	for len(val) >= 4 {
+		if err := writer.WriteUnsignedBitInt32(binary.LittleEndian.Uint32(val), 32); err != nil {
+			return err
+		}
+		val = val[4:]
+	}
 	for _, b := range val {
 		if err := writer.WriteByte(b); err != nil {
 			return err
@@ -93,23 +253,55 @@ func (writer *Writer) WriteInt64(val int64) error {
 
 // WriteUint64 writes a Uint64
 func (writer *Writer) WriteUint64(val uint64) error {
-	raw := make([]byte, 8)
-	binary.LittleEndian.PutUint64(raw, uint64(val))
-	err := writer.WriteUnsignedBitInt32(uint32(binary.LittleEndian.Uint32(raw[:4])), uint(unsafe.Sizeof(val))<<3)
-	if err != nil {
+	return writer.WriteUnsignedBitInt64(val, uint(unsafe.Sizeof(val))<<3)
+}
+
+// WriteFloat32 writes a float32
+func (writer *Writer) WriteFloat32(val float32) error {
+	return writer.WriteUint32(math.Float32bits(val))
+}
+
+// WriteFloat64 writes a float64
+func (writer *Writer) WriteFloat64(val float64) error {
+	return writer.WriteUint64(math.Float64bits(val))
+}
+
+// WriteOneBit writes a boolean as a single bit
+func (writer *Writer) WriteOneBit(value bool) error {
+	if value {
+		return writer.writeInternal(1, 1, false)
+	}
+	return writer.writeInternal(0, 1, false)
+}
+
+// WriteBool writes a boolean as a single bit.
+// It is equivalent to WriteOneBit.
+func (writer *Writer) WriteBool(value bool) error {
+	return writer.WriteOneBit(value)
+}
+
+// WriteBits writes a specific number of bits from data, as read by ReadBits.
+// data must be at least (numBits+7)/8 bytes long, otherwise io.ErrShortBuffer is returned.
+func (writer *Writer) WriteBits(data []byte, numBits uint) error {
+	if numBits > uint(len(data))<<3 {
+		return io.ErrShortBuffer
+	}
+	if err := writer.ensureInBounds(numBits); err != nil {
+		writer.currentBit = writer.totalBits
+		return err
+	}
+	if err := writer.WriteBytes(data[:numBits>>3]); err != nil {
 		return err
 	}
-	return writer.WriteUnsignedBitInt32(uint32(binary.LittleEndian.Uint32(raw[4:8])), uint(unsafe.Sizeof(val))<<3)
+	if numBits&7 == 0 {
+		return nil
+	}
+	return writer.WriteUnsignedBitInt32(uint32(data[numBits>>3]), numBits&7)
 }
 
 // WriteString writes a string, byte-by-byte
 func (writer *Writer) WriteString(val string) error {
-	for _, b := range []byte(val) {
-		if err := writer.WriteByte(b); err != nil {
-			return err
-		}
-	}
-	return nil
+	return writer.WriteBytes([]byte(val))
 }
 
 // WriteUnsignedBitInt32 writes a Uint32, but only the specified number of bits
@@ -124,6 +316,24 @@ func (writer *Writer) WriteUnsignedBitInt32(data uint32, numBits uint) error {
 	return writer.writeInternal(uint32(data), numBits, false)
 }
 
+// WriteUnsignedBitInt64 writes a Uint64, but only the specified number of bits
+func (writer *Writer) WriteUnsignedBitInt64(data uint64, numBits uint) error {
+	if numBits > 64 {
+		return fmt.Errorf("%w: cannot handle more than 64 bits in a single write", ErrWidthTooLarge)
+	}
+	if numBits <= 32 {
+		return writer.writeInternal(uint32(data), numBits, false)
+	}
+	// Both halves must fit, and are written before flushing, so the value is never split by an error
+	if err := writer.ensureInBounds(numBits); err != nil {
+		writer.currentBit = writer.totalBits
+		return err
+	}
+	writer.putBits(uint32(data), 32)
+	writer.putBits(uint32(data>>32), numBits-32)
+	return writer.flushCompleted()
+}
+
 // WriteSignedBitInt32 writes an Int32, but only the specified number of bits
 func (writer *Writer) WriteSignedBitInt32(data int32, numBits uint) error {
 	// Force the sign-extension bit to be correct even in the case of overflow.
@@ -136,24 +346,61 @@ func (writer *Writer) WriteSignedBitInt32(data int32, numBits uint) error {
 	return writer.writeInternal(uint32(nValue), numBits, false)
 }
 
+// WriteSignedBitInt64 writes an Int64, but only the specified number of bits
+func (writer *Writer) WriteSignedBitInt64(data int64, numBits uint) error {
+	if numBits == 0 || numBits > 64 {
+		return writer.WriteUnsignedBitInt64(uint64(data), numBits)
+	}
+	// Force the sign bit to be correct even in the case of overflow.
+	nValue := uint64(data) & (uint64(1)<<(numBits-1) - 1)
+	nValue |= (uint64(data) >> 63) << (numBits - 1)
+
+	return writer.WriteUnsignedBitInt64(nValue, numBits)
+}
+
+// WriteUBitLong writes the specified number of bits of an unsigned value.
+// It is equivalent to WriteUnsignedBitInt32, and named to match the Source engine.
+func (writer *Writer) WriteUBitLong(data uint32, numBits uint) error {
+	return writer.WriteUnsignedBitInt32(data, numBits)
+}
+
+// WriteSBitLong writes the specified number of bits of a signed value.
+// It is equivalent to WriteSignedBitInt32, and named to match the Source engine.
+func (writer *Writer) WriteSBitLong(data int32, numBits uint) error {
+	return writer.WriteSignedBitInt32(data, numBits)
+}
+
 func (writer *Writer) writeInternal(curData uint32, numBits uint, checkRange bool) error {
+	if numBits > 32 {
+		return fmt.Errorf("%w: cannot handle more than 32 bits in a single write", ErrWidthTooLarge)
+	}
+	if numBits == 0 {
+		return nil
+	}
 	if err := writer.ensureInBounds(numBits); err != nil {
 		writer.currentBit = writer.totalBits
 		return err
 	}
+	writer.putBits(curData, numBits)
+	return writer.flushCompleted()
+}
 
-	iCurBitMasked := writer.currentBit & 31
-	iDWord := uint32(writer.currentBit >> 5)
-	if writer.currentBit == writer.bitsWritten {
-		writer.bitsWritten += numBits
+// putBits writes 1 to 32 bits at the cursor, which must already be known to fit
+func (writer *Writer) putBits(curData uint32, numBits uint) {
+	relativeBit := writer.currentBit - writer.bufferOffset<<3
+	iCurBitMasked := relativeBit & 31
+	iDWord := uint32(relativeBit >> 5)
+	if writer.currentBit+numBits > writer.bitsWritten {
+		writer.bitsWritten = writer.currentBit + numBits
 	}
 	writer.currentBit += numBits
 
 	// Mask in a dword.
 	//Assert((iDWord * 4 + sizeof(long)) <= (unsigned int)m_nDataBytes)
-	pOut := make([]uint32, 2)
-	pOut[0], _ = bytesToUint32(writer.internalBuffer[(iDWord * 4) : (iDWord*4)+4])
-	pOut[1], _ = bytesToUint32(writer.internalBuffer[(iDWord*4)+4 : (iDWord*4)+8])
+	pOut := [2]uint32{
+		binary.LittleEndian.Uint32(writer.internalBuffer[iDWord*4:]),
+		binary.LittleEndian.Uint32(writer.internalBuffer[iDWord*4+4:]),
+	}
 
 	// Rotate data into dword alignment
 	curData = (curData << iCurBitMasked) | (curData >> (32 - iCurBitMasked))
@@ -175,22 +422,148 @@ func (writer *Writer) writeInternal(curData uint32, numBits uint, checkRange boo
 	// Note reversed order of writes so that dword1 wins if mask2 == 0 && i == 0
 	binary.LittleEndian.PutUint32(writer.internalBuffer[(iDWord*4)+(i*4):(iDWord*4)+(i*4)+4], dword2)
 	binary.LittleEndian.PutUint32(writer.internalBuffer[(iDWord*4):(iDWord*4)+4], dword1)
+}
 
+// flushCompleted writes every dword before the one containing the cursor to the
+// sink of a stream Writer, as they can no longer be modified.
+func (writer *Writer) flushCompleted() error {
+	if writer.sink == nil {
+		return nil
+	}
+	end := writer.currentBit
+	if writer.bitsWritten < end {
+		end = writer.bitsWritten
+	}
+	if numBytes := ((end - writer.bufferOffset<<3) >> 5) << 2; numBytes > 0 {
+		return writer.flushBytes(numBytes)
+	}
 	return nil
 }
 
+// flushBytes writes numBytes from the start of the buffer to the sink, and
+// shifts the remaining data down to take their place.
+// Bytes the sink accepts are consumed even if it fails, so that a later flush
+// sends only the bytes it did not.
+func (writer *Writer) flushBytes(numBytes uint) error {
+	if numBytes == 0 {
+		return nil
+	}
+	n, err := writer.sink.Write(writer.internalBuffer[:numBytes])
+	written := uint(0)
+	if n > 0 {
+		written = uint(n)
+	}
+	if written > numBytes {
+		written = numBytes
+	}
+	if err == nil && written < numBytes {
+		err = io.ErrShortWrite
+	}
+
+	remaining := copy(writer.internalBuffer, writer.internalBuffer[written:])
+	for i := remaining; i < len(writer.internalBuffer); i++ {
+		writer.internalBuffer[i] = 0
+	}
+	writer.bufferOffset += written
+	writer.totalBits += written << 3
+
+	if err != nil && writer.sticky {
+		writer.err = err
+	}
+	return err
+}
+
 func (writer *Writer) ensureInBounds(numBits uint) error {
-	if writer.currentBit+numBits > writer.totalBits {
-		return fmt.Errorf("bitbuf attempt oob write by %d bits", (writer.currentBit+numBits)-writer.totalBits)
+	return writer.ensureAvailable("write", numBits)
+}
+
+// ensureAvailable checks that numBits following the cursor can be used by op,
+// growing the buffer if possible.
+func (writer *Writer) ensureAvailable(op string, numBits uint) (err error) {
+	if writer.err != nil {
+		return writer.err
+	}
+
+	fits := !exceeds(writer.currentBit, numBits, writer.totalBits)
+	if !fits && writer.growable && !exceeds(writer.currentBit, numBits, math.MaxUint) {
+		err = writer.grow(op, writer.currentBit+numBits)
+	} else if !fits {
+		err = newBoundsError(op, writer.currentBit, numBits, writer.totalBits, nil)
+	}
+
+	if err != nil && writer.sticky {
+		writer.err = err
+	}
+	return err
+}
+
+// grow reallocates the buffer of a growable writer so that it can hold at least
+// requiredBits. Capacity is doubled where possible to amortise reallocation.
+func (writer *Writer) grow(op string, requiredBits uint) error {
+	if writer.maxBits != 0 && requiredBits > writer.maxBits {
+		return newBoundsError(op, writer.currentBit, requiredBits-writer.currentBit, writer.maxBits, nil)
+	}
+
+	// Lengths are relative to the start of the buffer, which for a stream
+	// Writer is not the start of the stream
+	length := (requiredBits+7)>>3 - writer.bufferOffset
+	if doubled := (writer.totalBits>>3 - writer.bufferOffset) * 2; doubled > length {
+		length = doubled
 	}
+	if writer.maxBits != 0 && length > writer.maxBits>>3-writer.bufferOffset {
+		length = writer.maxBits>>3 - writer.bufferOffset
+	}
+
+	internalBuffer := make([]byte, paddedLength(int(length)))
+	copy(internalBuffer, writer.internalBuffer)
+	writer.internalBuffer = internalBuffer
+	writer.totalBits = (writer.bufferOffset + length) << 3
+
 	return nil
 }
 
+// paddedLength returns the size of buffer needed to hold length bytes.
+// writeInternal always accesses whole dwords, and may touch the dword
+// following the one being written to, so the buffer is padded to allow that.
+func paddedLength(length int) int {
+	return (length+3)&^3 + 4
+}
+
 // NewWriter returns a new Bitbuf writer
 func NewWriter(length int) *Writer {
 	return &Writer{
-		internalBuffer: make([]byte, length+4),
+		internalBuffer: make([]byte, paddedLength(length+4)),
 		totalBits:      uint(length*8) + 32,
 		currentBit:     0,
 	}
 }
+
+// NewGrowableWriter returns a new Bitbuf writer that expands its buffer as data is written,
+// rather than failing once full.
+// capacityHint is the number of bytes to preallocate.
+// maxLength is the maximum number of bytes that can be written; 0 means no limit.
+func NewGrowableWriter(capacityHint int, maxLength int) *Writer {
+	if maxLength > 0 && capacityHint > maxLength {
+		capacityHint = maxLength
+	}
+	return &Writer{
+		internalBuffer: make([]byte, paddedLength(capacityHint)),
+		totalBits:      uint(capacityHint * 8),
+		currentBit:     0,
+		growable:       true,
+		maxBits:        uint(maxLength * 8),
+	}
+}
+
+// NewStreamWriter returns a new Bitbuf writer that writes completed bytes to sink as soon
+// as they can no longer be modified. Only the trailing partial dword is kept in memory.
+// Call Flush once done to write out any remaining data.
+func NewStreamWriter(sink io.Writer) *Writer {
+	return &Writer{
+		internalBuffer: make([]byte, paddedLength(4)),
+		totalBits:      32,
+		currentBit:     0,
+		growable:       true,
+		sink:           sink,
+	}
+}
@@ -1,8 +1,14 @@
 package bitbuf
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"reflect"
 	"testing"
+	"testing/quick"
 )
 
 func TestNewWriter(t *testing.T) {
@@ -67,3 +73,673 @@ func TestWriter_MultipleWrite(t *testing.T) {
 		}
 	}
 }
+
+func TestNewGrowableWriter(t *testing.T) {
+	if reflect.TypeOf(NewGrowableWriter(0, 0)) != reflect.TypeOf(&Writer{}) {
+		t.Errorf("unexpect type. Expected: %s, but received: %s", reflect.TypeOf(NewGrowableWriter(0, 0)), reflect.TypeOf(&Writer{}))
+	}
+}
+
+func TestWriter_Growable(t *testing.T) {
+	sut := NewGrowableWriter(1, 0)
+
+	expected := make([]int32, 0)
+	for i := int32(0); i < 100; i++ {
+		expected = append(expected, i*7919-123456)
+	}
+
+	if err := sut.WriteUnsignedBitInt32(5, 3); err != nil {
+		t.Error(err)
+	}
+	for _, v := range expected {
+		if err := sut.WriteInt32(v); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if sut.BitsWritten() != 3+32*uint(len(expected)) {
+		t.Errorf("expected: %d bits written, but received: %d", 3+32*len(expected), sut.BitsWritten())
+	}
+
+	reader := NewReader(sut.Data())
+	if val, err := reader.ReadUint32Bits(3); err != nil || val != 5 {
+		t.Errorf("expected: %d, but received: %d (%v)", 5, val, err)
+	}
+	for _, v := range expected {
+		if val, err := reader.ReadInt32(); err != nil || val != v {
+			t.Errorf("expected: %d, but received: %d (%v)", v, val, err)
+		}
+	}
+}
+
+func TestWriter_GrowableMaxLength(t *testing.T) {
+	sut := NewGrowableWriter(0, 6)
+
+	if err := sut.WriteInt32(212345); err != nil {
+		t.Error(err)
+	}
+	if err := sut.WriteInt16(1234); err != nil {
+		t.Error(err)
+	}
+	if err := sut.WriteInt8(12); err == nil {
+		t.Error("expected write beyond maximum length to fail")
+	}
+
+	expectedBytes := []byte{121, 61, 3, 0, 210, 4}
+	if len(sut.Data()) != len(expectedBytes) {
+		t.Errorf("expected: %d bytes, but received: %d", len(expectedBytes), len(sut.Data()))
+	}
+	for i, b := range sut.Data() {
+		if b != expectedBytes[i] {
+			t.Errorf("unexpected byte at position %d. expected %d, but received %d", int(i), uint8(expectedBytes[i]), uint8(b))
+		}
+	}
+}
+
+func TestNewStreamWriter(t *testing.T) {
+	if reflect.TypeOf(NewStreamWriter(&bytes.Buffer{})) != reflect.TypeOf(&Writer{}) {
+		t.Errorf("unexpect type. Expected: %s, but received: %s", reflect.TypeOf(NewStreamWriter(&bytes.Buffer{})), reflect.TypeOf(&Writer{}))
+	}
+}
+
+func TestWriter_Stream(t *testing.T) {
+	sink := &bytes.Buffer{}
+	sut := NewStreamWriter(sink)
+	expected := NewWriter(512)
+
+	for i := uint32(0); i < 100; i++ {
+		numBits := uint(i%32) + 1
+		val := (i * 2654435761) & (1<<numBits - 1)
+		if err := sut.WriteUnsignedBitInt32(val, numBits); err != nil {
+			t.Fatal(err)
+		}
+		if err := expected.WriteUnsignedBitInt32(val, numBits); err != nil {
+			t.Fatal(err)
+		}
+		if len(sut.Data()) > 4 {
+			t.Errorf("expected at most 4 unflushed bytes, but received: %d", len(sut.Data()))
+		}
+	}
+
+	if err := sut.Flush(); err != nil {
+		t.Error(err)
+	}
+	if len(sut.Data()) != 0 {
+		t.Errorf("expected no unflushed bytes, but received: %d", len(sut.Data()))
+	}
+	if !bytes.Equal(sink.Bytes(), expected.Data()) {
+		t.Errorf("expected: %v, but received: %v", expected.Data(), sink.Bytes())
+	}
+}
+
+func TestWriter_StreamFlushPadsByte(t *testing.T) {
+	sink := &bytes.Buffer{}
+	sut := NewStreamWriter(sink)
+
+	if err := sut.WriteUnsignedBitInt32(5, 3); err != nil {
+		t.Error(err)
+	}
+	if err := sut.Flush(); err != nil {
+		t.Error(err)
+	}
+	if err := sut.WriteUint8(124); err != nil {
+		t.Error(err)
+	}
+	if err := sut.Flush(); err != nil {
+		t.Error(err)
+	}
+
+	expectedBytes := []byte{5, 124}
+	if !bytes.Equal(sink.Bytes(), expectedBytes) {
+		t.Errorf("expected: %v, but received: %v", expectedBytes, sink.Bytes())
+	}
+	if sut.BitsWritten() != 16 {
+		t.Errorf("expected: %d bits written, but received: %d", 16, sut.BitsWritten())
+	}
+}
+
+func TestWriter_StreamSeek(t *testing.T) {
+	sink := &bytes.Buffer{}
+	sut := NewStreamWriter(sink)
+
+	if err := sut.WriteInt32(212345); err != nil {
+		t.Error(err)
+	}
+	if err := sut.WriteInt8(1); err != nil {
+		t.Error(err)
+	}
+
+	err := sut.Seek(16)
+	var seekErr *FlushedSeekError
+	if !errors.As(err, &seekErr) {
+		t.Fatalf("expected: %T, but received: %v", seekErr, err)
+	}
+	if seekErr.Position != 16 || seekErr.Flushed != 32 {
+		t.Errorf("unexpected seek error: %v", seekErr)
+	}
+
+	if err := sut.Seek(32); err != nil {
+		t.Error(err)
+	}
+	if err := sut.WriteInt8(124); err != nil {
+		t.Error(err)
+	}
+	if err := sut.Flush(); err != nil {
+		t.Error(err)
+	}
+
+	expectedBytes := []byte{121, 61, 3, 0, 124}
+	if !bytes.Equal(sink.Bytes(), expectedBytes) {
+		t.Errorf("expected: %v, but received: %v", expectedBytes, sink.Bytes())
+	}
+}
+
+func TestWriter_SeekBits(t *testing.T) {
+	sut := NewWriter(4)
+	if err := sut.WriteInt16(-2); err != nil {
+		t.Fatal(err)
+	}
+
+	if pos, err := sut.SeekBits(-4, io.SeekEnd); err != nil || pos != 12 {
+		t.Errorf("expected: %d, but received: %d (%v)", 12, pos, err)
+	}
+	if err := sut.WriteUnsignedBitInt32(0, 4); err != nil {
+		t.Fatal(err)
+	}
+	if pos, err := sut.SeekBits(-16, io.SeekCurrent); err != nil || pos != 0 {
+		t.Errorf("expected: %d, but received: %d (%v)", 0, pos, err)
+	}
+	expected := []byte{254, 15}
+	if !bytes.Equal(sut.Data(), expected) {
+		t.Errorf("expected: %v, but received: %v", expected, sut.Data())
+	}
+
+	if _, err := sut.SeekBits(-1, io.SeekStart); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	var boundsErr *BoundsError
+	if err := sut.Seek(1000); !errors.As(err, &boundsErr) || boundsErr.Op != "seek" {
+		t.Errorf("expected: seek *BoundsError, but received: %v", err)
+	}
+	if sut.BitsWritten() != 16 {
+		t.Errorf("expected: %d, but received: %d", 16, sut.BitsWritten())
+	}
+}
+
+func TestWriter_SkipBits(t *testing.T) {
+	sut := NewGrowableWriter(0, 0)
+	if err := sut.SkipBits(4); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteUnsignedBitInt32(15, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.SkipBytes(2); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{240, 0, 0}
+	if !bytes.Equal(sut.Data(), expected) {
+		t.Errorf("expected: %v, but received: %v", expected, sut.Data())
+	}
+
+	fixed := NewGrowableWriter(0, 2)
+	if err := fixed.SkipBytes(3); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if fixed.BitsWritten() != 0 {
+		t.Errorf("expected: %d, but received: %d", 0, fixed.BitsWritten())
+	}
+}
+
+func TestWriter_AlignToByte(t *testing.T) {
+	sut := NewWriter(4)
+	if err := sut.WriteUnsignedBitInt32(1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if sut.IsByteAligned() || sut.BitsToNextByte() != 5 {
+		t.Errorf("expected: %d, but received: %d", 5, sut.BitsToNextByte())
+	}
+	if err := sut.AlignToByte(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.AlignToByte(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteUnsignedBitInt32(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.AlignToByte(false); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{249, 1}
+	if !bytes.Equal(sut.Data(), expected) {
+		t.Errorf("expected: %v, but received: %v", expected, sut.Data())
+	}
+	if !sut.IsByteAligned() {
+		t.Error("expected writer to be aligned")
+	}
+}
+
+func TestWriter_WriteBytes_Aligned(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	sink := &bytes.Buffer{}
+	writers := []*Writer{NewWriter(16), NewGrowableWriter(0, 0), NewStreamWriter(sink)}
+	for _, sut := range writers {
+		if err := sut.WriteUint8(255); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.WriteBytes(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := sut.WriteString("ab"); err != nil {
+			t.Fatal(err)
+		}
+		if sut.BitsWritten() != 96 {
+			t.Errorf("expected: %d, but received: %d", 96, sut.BitsWritten())
+		}
+	}
+
+	expected := append(append([]byte{255}, data...), 'a', 'b')
+	for _, sut := range writers[:2] {
+		if !bytes.Equal(sut.Data(), expected) {
+			t.Errorf("expected: %v, but received: %v", expected, sut.Data())
+		}
+	}
+	if err := writers[2].Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sink.Bytes(), expected) {
+		t.Errorf("expected: %v, but received: %v", expected, sink.Bytes())
+	}
+
+	fixed := NewGrowableWriter(0, 4)
+	if err := fixed.WriteBytes(data); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+}
+
+func TestWriter_WriteUnsignedBitInt64(t *testing.T) {
+	value := uint64(0xA5C3F00F12345678)
+
+	for startBit := uint(0); startBit < 8; startBit++ {
+		for numBits := uint(1); numBits <= 64; numBits++ {
+			sut := NewWriter(16)
+			if err := sut.Seek(startBit); err != nil {
+				t.Error(err)
+			}
+			if err := sut.WriteUnsignedBitInt64(value, numBits); err != nil {
+				t.Error(err)
+			}
+
+			expected := value
+			if numBits < 64 {
+				expected &= (1 << numBits) - 1
+			}
+			reader := NewReader(sut.Data())
+			reader.Seek(int(startBit))
+			if val, err := reader.ReadUint64Bits(numBits); err != nil || val != expected {
+				t.Errorf("width %d at bit %d. expected: %d, but received: %d (%v)", numBits, startBit, expected, val, err)
+			}
+		}
+	}
+}
+
+func TestWriter_StickyErrors(t *testing.T) {
+	sut := NewGrowableWriter(0, 3)
+	sut.SetStickyErrors(true)
+
+	if err := sut.WriteUint16(1234); err != nil {
+		t.Error(err)
+	}
+	if err := sut.WriteUint16(1234); err == nil {
+		t.Error("expected oob write to fail")
+	}
+	// There is still room for a byte, but the writer has overflowed
+	if err := sut.WriteUint8(12); err == nil {
+		t.Error("expected write after overflow to fail")
+	}
+	if !sut.IsOverflowed() || sut.Err() == nil {
+		t.Error("expected writer to be overflowed")
+	}
+	if len(sut.Data()) != 2 {
+		t.Errorf("expected: %d bytes, but received: %d", 2, len(sut.Data()))
+	}
+}
+
+// failingWriter is a sink that always returns err
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestWriter_StickyErrorsStreamSink(t *testing.T) {
+	sinkErr := errors.New("sink failed")
+	sut := NewStreamWriter(failingWriter{err: sinkErr})
+	sut.SetStickyErrors(true)
+
+	// Nothing reaches the sink until a whole dword is complete
+	if err := sut.WriteUint16(1234); err != nil {
+		t.Error(err)
+	}
+	if err := sut.WriteUint32(1234); !errors.Is(err, sinkErr) {
+		t.Errorf("expected: %v, but received: %v", sinkErr, err)
+	}
+	if err := sut.WriteUint8(12); !errors.Is(err, sinkErr) {
+		t.Errorf("expected: %v, but received: %v", sinkErr, err)
+	}
+	if err := sut.Flush(); !errors.Is(err, sinkErr) {
+		t.Errorf("expected: %v, but received: %v", sinkErr, err)
+	}
+	if !errors.Is(sut.Err(), sinkErr) || sut.IsOverflowed() {
+		t.Errorf("expected: %v, but received: %v", sinkErr, sut.Err())
+	}
+}
+
+func TestWriter_StreamShortWrite(t *testing.T) {
+	sut := NewStreamWriter(shortWriter{})
+	if err := sut.WriteUint8(1); err != nil {
+		t.Error(err)
+	}
+	if err := sut.Flush(); !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("expected: %v, but received: %v", io.ErrShortWrite, err)
+	}
+}
+
+// partialWriter is a sink whose first write accepts only accept bytes, and fails
+type partialWriter struct {
+	accept int
+	failed bool
+	bytes.Buffer
+}
+
+var errPartialWrite = errors.New("partial write")
+
+func (w *partialWriter) Write(p []byte) (int, error) {
+	if !w.failed {
+		w.failed = true
+		n, _ := w.Buffer.Write(p[:w.accept])
+		return n, errPartialWrite
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestWriter_StreamPartialWrite(t *testing.T) {
+	sink := &partialWriter{accept: 2}
+	sut := NewStreamWriter(sink)
+	if err := sut.WriteUint32(0x04030201); err != errPartialWrite {
+		t.Errorf("expected: %v, but received: %v", errPartialWrite, err)
+	}
+	if err := sut.WriteUint32(0x08070605); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.WriteUint32(0x0c0b0a09); err != nil {
+		t.Fatal(err)
+	}
+	if err := sut.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bytes accepted before the failure are not sent again
+	expected := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	if !bytes.Equal(sink.Bytes(), expected) {
+		t.Errorf("expected: %v, but received: %v", expected, sink.Bytes())
+	}
+}
+
+// shortWriter is a sink that accepts no bytes, without returning an error
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) {
+	return 0, nil
+}
+
+// roundTrip writes a value with write, starting offset bits into the buffer, and
+// returns a Reader positioned at the start of it.
+func roundTrip(t *testing.T, offset uint8, write func(*Writer) error) *Reader {
+	writer := NewGrowableWriter(0, 0)
+	if err := writer.WriteUnsignedBitInt32(0x5a5a5a5a, uint(offset%32)); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(writer); err != nil {
+		t.Fatal(err)
+	}
+	reader := NewReader(writer.Data())
+	if err := reader.Seek(int(offset % 32)); err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func TestWriter_RoundTrip_FixedWidth(t *testing.T) {
+	f := func(offset uint8, u8 uint8, i8 int8, u16 uint16, i16 int16, u32 uint32, i32 int32, u64 uint64, i64 int64, b bool) bool {
+		reader := roundTrip(t, offset, func(w *Writer) error {
+			w.SetStickyErrors(true)
+			w.WriteUint8(u8)
+			w.WriteInt8(i8)
+			w.WriteUint16(u16)
+			w.WriteInt16(i16)
+			w.WriteUint32(u32)
+			w.WriteInt32(i32)
+			w.WriteUint64(u64)
+			w.WriteInt64(i64)
+			w.WriteOneBit(b)
+			w.WriteBool(!b)
+			w.WriteByte(u8)
+			return w.Err()
+		})
+		reader.SetStickyErrors(true)
+		ru8, _ := reader.ReadUint8()
+		ri8, _ := reader.ReadInt8()
+		ru16, _ := reader.ReadUint16()
+		ri16, _ := reader.ReadInt16()
+		ru32, _ := reader.ReadUint32()
+		ri32, _ := reader.ReadInt32()
+		ru64, _ := reader.ReadUint64()
+		ri64, _ := reader.ReadInt64()
+		rb, _ := reader.ReadOneBit()
+		rnb, _ := reader.ReadOneBit()
+		rbyte, _ := reader.ReadByte()
+		return reader.Err() == nil &&
+			ru8 == u8 && ri8 == i8 && ru16 == u16 && ri16 == i16 && ru32 == u32 && ri32 == i32 &&
+			ru64 == u64 && ri64 == i64 && rb == b && rnb == !b && rbyte == u8
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriter_RoundTrip_Floats(t *testing.T) {
+	f := func(offset uint8, f32 float32, f64 float64) bool {
+		reader := roundTrip(t, offset, func(w *Writer) error {
+			if err := w.WriteFloat32(f32); err != nil {
+				return err
+			}
+			return w.WriteFloat64(f64)
+		})
+		r32, err := reader.ReadFloat32()
+		if err != nil {
+			return false
+		}
+		r64, err := reader.ReadFloat64()
+		return err == nil && math.Float32bits(r32) == math.Float32bits(f32) && math.Float64bits(r64) == math.Float64bits(f64)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriter_RoundTrip_VariableWidth(t *testing.T) {
+	f := func(offset uint8, width uint8, value uint64) bool {
+		numBits := uint(width%64) + 1
+		reader := roundTrip(t, offset, func(w *Writer) error {
+			if err := w.WriteUnsignedBitInt64(value, numBits); err != nil {
+				return err
+			}
+			if err := w.WriteSignedBitInt64(int64(value), numBits); err != nil {
+				return err
+			}
+			if numBits > 32 {
+				return nil
+			}
+			if err := w.WriteUBitLong(uint32(value), numBits); err != nil {
+				return err
+			}
+			return w.WriteSBitLong(int32(value), numBits)
+		})
+
+		mask := uint64(math.MaxUint64) >> (64 - numBits)
+		unsigned, err := reader.ReadUint64Bits(numBits)
+		if err != nil || unsigned != value&mask {
+			return false
+		}
+		// The sign bit is always written, even if the value overflows
+		signed, err := reader.ReadInt64Bits(numBits)
+		if err != nil || uint64(signed)&(mask>>1) != value&(mask>>1) || (signed < 0) != (int64(value) < 0) {
+			return false
+		}
+		if numBits > 32 {
+			return true
+		}
+		unsigned32, err := reader.ReadUBitLong(numBits)
+		if err != nil || uint64(unsigned32) != value&mask {
+			return false
+		}
+		signed32, err := reader.ReadSBitLong(numBits)
+		return err == nil && uint64(uint32(signed32))&(mask>>1) == value&(mask>>1) && (signed32 < 0) == (int32(value) < 0)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriter_RoundTrip_Bits(t *testing.T) {
+	f := func(offset uint8, data []byte, trim uint8) bool {
+		numBits := uint(len(data)) << 3
+		if numBits > 0 {
+			numBits -= uint(trim % 8)
+		}
+		reader := roundTrip(t, offset, func(w *Writer) error {
+			if err := w.WriteBits(data, numBits); err != nil {
+				return err
+			}
+			return w.WriteBytes(data)
+		})
+
+		bits, err := reader.ReadBits(numBits)
+		if err != nil {
+			return false
+		}
+		expected := append([]byte{}, data...)
+		if numBits&7 != 0 {
+			expected[len(expected)-1] &= byte(1)<<(numBits&7) - 1
+		}
+		raw, err := reader.ReadBytes(uint(len(data)))
+		return err == nil && bytes.Equal(bits, expected) && bytes.Equal(raw, data)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriter_WriteBits_ShortBuffer(t *testing.T) {
+	sut := NewWriter(4)
+	if err := sut.WriteBits([]byte{1}, 9); err != io.ErrShortBuffer {
+		t.Errorf("expected: %v, but received: %v", io.ErrShortBuffer, err)
+	}
+	if sut.BitsWritten() != 0 {
+		t.Errorf("expected: %d, but received: %d", 0, sut.BitsWritten())
+	}
+}
+
+func TestWriter_HugeLength(t *testing.T) {
+	sut := NewGrowableWriter(0, 0)
+	sut.WriteByte(1)
+	if err := sut.WriteBits([]byte{1}, math.MaxUint); err != io.ErrShortBuffer {
+		t.Errorf("expected: %v, but received: %v", io.ErrShortBuffer, err)
+	}
+	if err := sut.SkipBits(math.MaxUint); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+
+	fixed := NewWriter(4)
+	fixed.WriteByte(1)
+	if err := fixed.SkipBits(math.MaxUint - 4); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if sut.BitsWritten() != 8 || fixed.BitsWritten() != 8 {
+		t.Errorf("expected: %d, but received: %d and %d", 8, sut.BitsWritten(), fixed.BitsWritten())
+	}
+}
+
+func TestWriter_WriteUnsignedBitInt64_Partial(t *testing.T) {
+	sut := NewGrowableWriter(0, 6)
+	sut.WriteUint8(1)
+	if err := sut.WriteUnsignedBitInt64(math.MaxUint64, 48); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("expected: %v, but received: %v", ErrOutOfBounds, err)
+	}
+	if sut.BitsWritten() != 8 {
+		t.Errorf("expected: %d, but received: %d", 8, sut.BitsWritten())
+	}
+
+	// A sink error is returned once the whole value has been written
+	sinkErr := errors.New("sink failed")
+	stream := NewStreamWriter(failingWriter{err: sinkErr})
+	if err := stream.WriteUnsignedBitInt64(math.MaxUint64, 64); !errors.Is(err, sinkErr) {
+		t.Errorf("expected: %v, but received: %v", sinkErr, err)
+	}
+	if stream.BitsWritten() != 64 {
+		t.Errorf("expected: %d, but received: %d", 64, stream.BitsWritten())
+	}
+}
+
+func TestWriter_WriteUnsignedBitInt64_TooWide(t *testing.T) {
+	sut := NewWriter(16)
+	if err := sut.WriteUnsignedBitInt64(1, 65); !errors.Is(err, ErrWidthTooLarge) {
+		t.Errorf("expected: %v, but received: %v", ErrWidthTooLarge, err)
+	}
+	if sut.BitsWritten() != 0 {
+		t.Errorf("expected: %d, but received: %d", 0, sut.BitsWritten())
+	}
+}
+
+func BenchmarkWriter_WriteUnsignedBitInt32(b *testing.B) {
+	for _, numBits := range []uint{1, 5, 8, 13, 32} {
+		b.Run(fmt.Sprintf("%d", numBits), func(b *testing.B) {
+			sut := NewWriter(64 * 1024)
+			limit := uint(64*1024*8) - 32
+
+			b.SetBytes(int64(numBits+7) / 8)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if sut.currentBit > limit {
+					sut.Seek(0)
+				}
+				if err := sut.WriteUnsignedBitInt32(uint32(i), numBits); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkWriter_WriteBytes(b *testing.B) {
+	data := benchmarkData()[:256]
+	for _, startBit := range []uint{0, 3} {
+		b.Run(fmt.Sprintf("start%d", startBit), func(b *testing.B) {
+			sut := NewWriter(len(data) + 1)
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sut.Seek(startBit)
+				if err := sut.WriteBytes(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}